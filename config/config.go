@@ -0,0 +1,125 @@
+// Package config loads and saves the user-adjustable application settings
+package config
+
+import (
+	"encoding/json"
+	"katana/tracker"
+	"os"
+	"path/filepath"
+)
+
+// TemplatesDir holds the user's export templates, e.g. weekly.md.tmpl
+var TemplatesDir = filepath.Join("data", "templates")
+
+// Config holds application configuration
+type Config struct {
+	NotificationThresholdHours float64 `json:"notification_threshold_hours"`
+	AutoExportEnabled         bool    `json:"auto_export_enabled"`
+	ExportPath               string  `json:"export_path"`
+	TimerUpdateIntervalMs    int     `json:"timer_update_interval_ms"`
+	MaxActivityLength        int     `json:"max_activity_length"`
+	MaxTagLength            int     `json:"max_tag_length"`
+	MaxTags                 int     `json:"max_tags"`
+	IdleThresholdMinutes    int     `json:"idle_threshold_minutes"` // 0 disables idle detection
+
+	// Pomodoro phase lengths in minutes
+	PomodoroWorkMinutes       int `json:"pomodoro_work_minutes"`
+	PomodoroShortBreakMinutes int `json:"pomodoro_short_break_minutes"`
+	PomodoroLongBreakMinutes  int `json:"pomodoro_long_break_minutes"`
+	PomodoroCycles            int `json:"pomodoro_cycles"` // Work intervals before a long break
+
+	Goals                []tracker.Goal `json:"goals"`
+	BudgetWarningPercent int            `json:"budget_warning_percent"` // Warn when a budget reaches this share
+
+	BillingRounding    tracker.RoundingRule `json:"billing_rounding"`
+	InvoicePrefix      string               `json:"invoice_prefix"`
+	InvoiceIssuer      string               `json:"invoice_issuer"` // Your name and address, printed on invoices
+	InvoiceTaxPercent  float64              `json:"invoice_tax_percent"`
+	InvoiceDueDays     int                  `json:"invoice_due_days"`
+	ExportIncludeNotes bool                 `json:"export_include_notes"` // Add session notes to exports
+
+	OverlapPolicy tracker.OverlapPolicy `json:"overlap_policy"` // How concurrent timers count toward totals
+
+	SplitAtMidnight bool `json:"split_at_midnight"` // Save running sessions at midnight and continue them
+
+	Templates []tracker.Template `json:"templates"` // Favorite activities, the first nine have shortcuts
+
+	Habits            []tracker.Habit `json:"habits"`
+	HabitReminderHour int             `json:"habit_reminder_hour"` // Hour of the evening reminder for unmet habits, 0 disables it
+}
+
+// DefaultConfig returns the default configuration
+func DefaultConfig() *Config {
+	return &Config{
+		NotificationThresholdHours: 2.0,
+		AutoExportEnabled:         false,
+		ExportPath:               ".",
+		TimerUpdateIntervalMs:    500,
+		MaxActivityLength:        100,
+		MaxTagLength:            20,
+		MaxTags:                 5,
+		IdleThresholdMinutes:    5,
+
+		PomodoroWorkMinutes:       25,
+		PomodoroShortBreakMinutes: 5,
+		PomodoroLongBreakMinutes:  15,
+		PomodoroCycles:            4,
+
+		Goals:                []tracker.Goal{},
+		BudgetWarningPercent: 90,
+
+		BillingRounding: tracker.RoundingRule{
+			IncrementMinutes: 6,
+			Mode:             tracker.RoundUp,
+			Scope:            tracker.PerSession,
+		},
+		InvoicePrefix:  "INV",
+		InvoiceDueDays: 30,
+
+		OverlapPolicy: tracker.CountPerSession,
+
+		SplitAtMidnight: true,
+
+		Templates: []tracker.Template{},
+
+		Habits:            []tracker.Habit{},
+		HabitReminderHour: 20,
+	}
+}
+
+// LoadConfig loads configuration from file or creates default
+func LoadConfig() (*Config, error) {
+	configPath := filepath.Join("data", "config.json")
+	
+	// Create data directory if it doesn't exist
+	os.MkdirAll("data", 0755)
+	
+	// If config file doesn't exist, create it with defaults
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		config := DefaultConfig()
+		return config, config.Save()
+	}
+	
+	// Load existing config
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return DefaultConfig(), err
+	}
+	
+	config := DefaultConfig()
+	if err := json.Unmarshal(data, config); err != nil {
+		return DefaultConfig(), err
+	}
+	
+	return config, nil
+}
+
+// Save saves the configuration to file
+func (c *Config) Save() error {
+	configPath := filepath.Join("data", "config.json")
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(configPath, data, 0644)
+}
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"katana/tracker"
+	"os"
+	"path/filepath"
+)
+
+// RulesPath is the auto-categorization rules file, edited by hand
+var RulesPath = filepath.Join("data", "rules.json")
+
+// rulesFile is the layout of the rules file
+type rulesFile struct {
+	Rules []tracker.Rule `json:"rules"`
+}
+
+// LoadRules reads and compiles the auto-categorization rules, creating an
+// empty rules file on first use
+func LoadRules() (*tracker.RuleSet, error) {
+	data, err := os.ReadFile(RulesPath)
+	if os.IsNotExist(err) {
+		os.MkdirAll(filepath.Dir(RulesPath), 0755)
+		empty, _ := json.MarshalIndent(rulesFile{Rules: []tracker.Rule{}}, "", "  ")
+		rs, _ := tracker.NewRuleSet(nil)
+		return rs, os.WriteFile(RulesPath, empty, 0644)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var file rulesFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid rules file %s: %w", RulesPath, err)
+	}
+	rs, err := tracker.NewRuleSet(file.Rules)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rules file %s: %w", RulesPath, err)
+	}
+	return rs, nil
+}
@@ -3,192 +3,194 @@ package export
 import (
 	"encoding/csv"
 	"encoding/json"
+	"fmt"
+	"io"
 	"katana/tracker"
-	"os"
 	"time"
-	"fmt"
-	"strings"
-
-	"github.com/jung-kurt/gofpdf"
 )
 
-// ExportToCSV exports sessions to a CSV file
-func ExportToCSV(sessions []*tracker.Session, filename string) error {
-	f, err := os.Create(filename)
+// Options selects optional content for the session exports
+type Options struct {
+	IncludeNotes bool                   // Add each session's notes
+	Goals        []tracker.GoalProgress // Goal attainment rows appended after the sessions
+	Columns      []Column               // Columns of the CSV and PDF exports; empty means DefaultColumns
+}
+
+// ExportToCSV exports sessions to a CSV file, followed by any goal attainment rows
+//
+// Deprecated: use ExportRange with CSV.
+func ExportToCSV(sessions []*tracker.Session, filename string, opts Options) error {
+	return exportSessions(CSV, sessions, filename, opts, "")
+}
+
+// ExportToPDF exports sessions to a PDF report, followed by any goal attainment rows
+//
+// Deprecated: use ExportRange with PDF.
+func ExportToPDF(sessions []*tracker.Session, filename string, opts Options) error {
+	return exportSessions(PDF, sessions, filename, opts, "Tracked Sessions")
+}
+
+// ExportMonthlyToCSV exports all sessions of the given month grouped by day
+//
+// Deprecated: use ExportRange with CSVByDay.
+func ExportMonthlyToCSV(storage monthLoader, year int, month time.Month, filename string, opts Options) error {
+	return exportMonth(CSVByDay, storage, year, month, filename, opts)
+}
+
+// ExportMonthlyToPDF exports all sessions of the given month grouped by day
+//
+// Deprecated: use ExportRange with PDF.
+func ExportMonthlyToPDF(storage monthLoader, year int, month time.Month, filename string, opts Options) error {
+	return exportMonth(PDF, storage, year, month, filename, opts)
+}
+
+// monthLoader is the storage the monthly exports read from
+type monthLoader interface {
+	LoadSessionsForMonth(year int, month time.Month) ([]*tracker.Session, error)
+}
+
+// exportSessions exports sessions over the days they span with e
+func exportSessions(e Exporter, sessions []*tracker.Session, filename string, opts Options, title string) error {
+	start := tracker.DayStart(time.Now())
+	end := start.AddDate(0, 0, 1)
+	for i, s := range sessions {
+		if first := tracker.DayStart(s.StartTime); i == 0 || first.Before(start) {
+			start = first
+		}
+		if last := tracker.DayStart(s.EndTime).AddDate(0, 0, 1); i == 0 || last.After(end) {
+			end = last
+		}
+	}
+	return ExportRange(e, sessionList(sessions), Report{Title: title, Start: start, End: end, Options: opts}, filename)
+}
+
+// exportMonth exports a month of sessions from storage with e
+func exportMonth(e Exporter, storage monthLoader, year int, month time.Month, filename string, opts Options) error {
+	sessions, err := storage.LoadSessionsForMonth(year, month)
 	if err != nil {
 		return err
 	}
-	defer f.Close()
-	w := csv.NewWriter(f)
-	defer w.Flush()
-	w.Write([]string{"Start Time", "End Time", "Duration (min)", "Activity", "Category", "Tags"})
-	for _, s := range sessions {
-		tags := ""
-		if len(s.Tags) > 0 {
-			tags = jsonTags(s.Tags)
+	start := time.Date(year, month, 1, 0, 0, 0, 0, time.Local)
+	r := Report{
+		Title:   fmt.Sprintf("Time Tracking Report - %s %d", month, year),
+		Start:   start,
+		End:     start.AddDate(0, 1, 0),
+		Options: opts,
+	}
+	return ExportRange(e, sessionList(sessions), r, filename)
+}
+
+// sessionList is a Storage over sessions already in memory
+type sessionList []*tracker.Session
+
+func (l sessionList) LoadSessionsInRange(start, end time.Time) ([]*tracker.Session, error) {
+	var sessions []*tracker.Session
+	for _, s := range l {
+		if s.StartTime.Before(end) && s.EndTime.After(start) {
+			sessions = append(sessions, s)
 		}
-		w.Write([]string{
-			s.StartTime.Format(time.RFC3339),
-			s.EndTime.Format(time.RFC3339),
-			formatMinutes(s.Duration),
-			s.Activity,
-			s.Category,
-			tags,
-		})
 	}
-	return nil
+	return sessions, nil
 }
 
-// ExportToPDF exports sessions to a PDF file
-func ExportToPDF(sessions []*tracker.Session, filename string) error {
-	pdf := gofpdf.New("P", "mm", "A4", "")
-	pdf.AddPage()
-	pdf.SetFont("Arial", "B", 14)
-	pdf.Cell(40, 10, "Tracked Sessions")
-	pdf.Ln(12)
-	pdf.SetFont("Arial", "", 10)
+func (l sessionList) EachSessionInRange(start, end time.Time, fn func(*tracker.Session) error) error {
+	sessions, _ := l.LoadSessionsInRange(start, end)
 	for _, s := range sessions {
-		row := s.StartTime.Format("2006-01-02 15:04") + " - " + s.EndTime.Format("15:04") +
-			" | " + s.Activity + " | " + s.Category + " | " + jsonTags(s.Tags) + " | " + formatMinutes(s.Duration) + " min"
-		pdf.Cell(0, 8, row)
-		pdf.Ln(8)
+		if err := fn(s); err != nil {
+			return err
+		}
 	}
-	return pdf.OutputFileAndClose(filename)
+	return nil
 }
 
-// ExportMonthlyToCSV exports all sessions for current month grouped by day
-func ExportMonthlyToCSV(storage interface{ LoadSessionsForMonth(int, time.Month) ([]*tracker.Session, error) }, filename string) error {
-	now := time.Now()
-	sessions, err := storage.LoadSessionsForMonth(now.Year(), now.Month())
-	if err != nil {
-		return err
-	}
+// CSVByDay writes every day of the report with its sessions and the day's
+// total on the first row; days without sessions get a "No activity" row
+var CSVByDay Exporter = csvByDayExporter{}
 
-	f, err := os.Create(filename)
-	if err != nil {
-		return err
-	}
-	defer f.Close()
-	w := csv.NewWriter(f)
-	defer w.Flush()
+type csvByDayExporter struct{}
+
+func (csvByDayExporter) Name() string      { return "CSV by Day" }
+func (csvByDayExporter) Extension() string { return "csv" }
+func (csvByDayExporter) MIME() string      { return "text/csv" }
+
+func (csvByDayExporter) Write(out io.Writer, r Report) error {
+	w := csv.NewWriter(out)
+	opts := r.Options
 
 	// Write header
-	w.Write([]string{"Date", "Start Time", "End Time", "Duration (min)", "Activity", "Category", "Tags", "Daily Total (min)"})
+	w.Write(withNotesColumn([]string{"Date", "Start Time", "End Time", "Duration (min)", "Activity", "Category", "Tags", "Daily Total (min)"}, "Notes", opts))
 
-	// Group sessions by day
-	dailySessions := make(map[string][]*tracker.Session)
-	dailyTotals := make(map[string]float64)
-	
-	for _, s := range sessions {
-		dayKey := s.StartTime.Format("2006-01-02")
-		dailySessions[dayKey] = append(dailySessions[dayKey], s)
-		dailyTotals[dayKey] += s.Duration.Minutes()
+	// Group sessions by day; a session crossing midnight counts on both days
+	dailySessions := make(map[time.Time][]*tracker.Session)
+	dailyTotals := make(map[time.Time]float64)
+
+	for _, part := range r.Parts() {
+		day := tracker.DayStart(part.StartTime.Local())
+		dailySessions[day] = append(dailySessions[day], part)
+		dailyTotals[day] += part.Duration.Minutes()
 	}
 
-	// Get all days in current month
-	firstDay := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
-	lastDay := firstDay.AddDate(0, 1, -1)
-	
-	for d := firstDay; d.Day() <= lastDay.Day(); d = d.AddDate(0, 0, 1) {
+	for d := tracker.DayStart(r.Start.Local()); d.Before(r.End); d = d.AddDate(0, 0, 1) {
 		dayKey := d.Format("2006-01-02")
-		daySessions := dailySessions[dayKey]
-		
+		daySessions := dailySessions[d]
+
 		if len(daySessions) == 0 {
 			// No sessions for this day
-			w.Write([]string{dayKey, "", "", "", "No activity", "", "", "0.0"})
-		} else {
-			// Write sessions for this day
-			for i, s := range daySessions {
-				tags := ""
-				if len(s.Tags) > 0 {
-					tags = jsonTags(s.Tags)
-				}
-				dailyTotal := ""
-				if i == 0 { // Only show daily total on first row of the day
-					dailyTotal = fmt.Sprintf("%.1f", dailyTotals[dayKey])
-				}
-				w.Write([]string{
-					dayKey,
-					s.StartTime.Format("15:04"),
-					s.EndTime.Format("15:04"),
-					formatMinutes(s.Duration),
-					s.Activity,
-					s.Category,
-					tags,
-					dailyTotal,
-				})
+			w.Write(withNotesColumn([]string{dayKey, "", "", "", "No activity", "", "", "0.0"}, "", opts))
+			continue
+		}
+		// Write sessions for this day
+		for i, s := range daySessions {
+			tags := ""
+			if len(s.Tags) > 0 {
+				tags = jsonTags(s.Tags)
 			}
+			dailyTotal := ""
+			if i == 0 { // Only show daily total on first row of the day
+				dailyTotal = fmt.Sprintf("%.1f", dailyTotals[d])
+			}
+			w.Write(withNotesColumn([]string{
+				dayKey,
+				s.StartTime.Format("15:04"),
+				s.EndTime.Format("15:04"),
+				formatMinutes(s.Duration),
+				s.Activity,
+				s.Category,
+				tags,
+				dailyTotal,
+			}, s.Notes, opts))
 		}
 	}
-	return nil
+	writeGoalsCSV(w, opts.Goals)
+	w.Flush()
+	return w.Error()
 }
 
-// ExportMonthlyToPDF exports all sessions for current month grouped by day
-func ExportMonthlyToPDF(storage interface{ LoadSessionsForMonth(int, time.Month) ([]*tracker.Session, error) }, filename string) error {
-	now := time.Now()
-	sessions, err := storage.LoadSessionsForMonth(now.Year(), now.Month())
-	if err != nil {
-		return err
+// writeGoalsCSV appends a goal attainment section after a blank row
+func writeGoalsCSV(w *csv.Writer, goals []tracker.GoalProgress) {
+	if len(goals) == 0 {
+		return
 	}
-
-	pdf := gofpdf.New("P", "mm", "A4", "")
-	pdf.AddPage()
-	pdf.SetFont("Arial", "B", 16)
-	pdf.Cell(0, 10, fmt.Sprintf("Time Tracking Report - %s %d", now.Month().String(), now.Year()))
-	pdf.Ln(15)
-
-	// Group sessions by day
-	dailySessions := make(map[string][]*tracker.Session)
-	dailyTotals := make(map[string]float64)
-	
-	for _, s := range sessions {
-		dayKey := s.StartTime.Format("2006-01-02")
-		dailySessions[dayKey] = append(dailySessions[dayKey], s)
-		dailyTotals[dayKey] += s.Duration.Minutes()
+	w.Write([]string{})
+	w.Write([]string{"Goal", "Period Start", "Tracked (h)", "Target (h)", "Progress (%)", "Status"})
+	for _, g := range goals {
+		w.Write([]string{
+			g.Goal.Label(),
+			g.PeriodStart.Format("2006-01-02"),
+			fmt.Sprintf("%.1f", g.Tracked.Hours()),
+			fmt.Sprintf("%.1f", g.Goal.Hours),
+			fmt.Sprintf("%.0f", g.Fraction()*100),
+			g.Status(),
+		})
 	}
+}
 
-	// Get all days in current month
-	firstDay := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
-	lastDay := firstDay.AddDate(0, 1, -1)
-	
-	monthlyTotal := 0.0
-	
-	for d := firstDay; d.Day() <= lastDay.Day(); d = d.AddDate(0, 0, 1) {
-		dayKey := d.Format("2006-01-02")
-		daySessions := dailySessions[dayKey]
-		dayTotal := dailyTotals[dayKey]
-		monthlyTotal += dayTotal
-		
-		// Day header
-		pdf.SetFont("Arial", "B", 12)
-		if dayTotal > 0 {
-			pdf.Cell(0, 8, fmt.Sprintf("%s (%s) - %.1f minutes", dayKey, d.Format("Monday"), dayTotal))
-		} else {
-			pdf.Cell(0, 8, fmt.Sprintf("%s (%s) - No activity", dayKey, d.Format("Monday")))
-		}
-		pdf.Ln(10)
-		
-		if len(daySessions) > 0 {
-			pdf.SetFont("Arial", "", 10)
-			for _, s := range daySessions {
-				sessionText := fmt.Sprintf("  %s - %s | %s | %s | %.1f min",
-					s.StartTime.Format("15:04"),
-					s.EndTime.Format("15:04"),
-					s.Activity,
-					strings.Join(s.Tags, ", "),
-					s.Duration.Minutes())
-				pdf.Cell(0, 6, sessionText)
-				pdf.Ln(6)
-			}
-		}
-		pdf.Ln(4)
+// withNotesColumn appends notes as the last column when notes are requested
+func withNotesColumn(row []string, notes string, opts Options) []string {
+	if !opts.IncludeNotes {
+		return row
 	}
-	
-	// Monthly summary
-	pdf.SetFont("Arial", "B", 14)
-	pdf.Cell(0, 10, fmt.Sprintf("Monthly Total: %.1f minutes (%.1f hours)", monthlyTotal, monthlyTotal/60))
-	
-	return pdf.OutputFileAndClose(filename)
+	return append(row, notes)
 }
 
 func jsonTags(tags []string) string {
@@ -0,0 +1,155 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"katana/tracker"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Exporter writes a report in one file format. Formats register themselves
+// with Register and the UI offers every registered format.
+type Exporter interface {
+	Name() string      // Shown in the export menus, e.g. "CSV"
+	Extension() string // Of the exported file, without the dot
+	MIME() string
+	Write(w io.Writer, r Report) error
+}
+
+// Streamer is implemented by exporters that write sessions straight from
+// storage, one at a time, instead of from Report.Sessions
+type Streamer interface {
+	Stream(w io.Writer, storage SessionStream, r Report) error
+}
+
+// Storage is the storage the exports read from
+type Storage interface {
+	RangeLoader
+	SessionStream
+}
+
+// Report is what an exporter writes: the sessions of a range of days
+type Report struct {
+	Title    string // Empty means "Time Tracking Report"
+	Start    time.Time
+	End      time.Time // Exclusive
+	Filter   Filter
+	Options  Options
+	Sessions []*tracker.Session // Whole sessions overlapping the range that pass the filter, by start time
+}
+
+// title returns the report's title or the default one
+func (r Report) title() string {
+	if r.Title == "" {
+		return "Time Tracking Report"
+	}
+	return r.Title
+}
+
+// Parts returns the sessions clipped to the range and cut at midnight into
+// one part per day, sorted by start time
+func (r Report) Parts() []*tracker.Session {
+	var parts []*tracker.Session
+	for _, s := range tracker.ClipSessions(r.Sessions, r.Start, r.End) {
+		parts = append(parts, tracker.SplitByDay(s)...)
+	}
+	sort.SliceStable(parts, func(i, j int) bool { return parts[i].StartTime.Before(parts[j].StartTime) })
+	return parts
+}
+
+// Load fills Sessions with the range's sessions that pass the filter
+func (r *Report) Load(storage RangeLoader) error {
+	sessions, err := storage.LoadSessionsInRange(r.Start, r.End)
+	if err != nil {
+		return err
+	}
+	r.Sessions = nil
+	for _, s := range sessions {
+		if r.Filter.Matches(s) {
+			r.Sessions = append(r.Sessions, s)
+		}
+	}
+	sort.SliceStable(r.Sessions, func(i, j int) bool { return r.Sessions[i].StartTime.Before(r.Sessions[j].StartTime) })
+	return nil
+}
+
+// ExportRange writes the report for the range of r to filename, loading
+// its sessions from storage unless the exporter streams them
+func ExportRange(e Exporter, storage Storage, r Report, filename string) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if s, ok := e.(Streamer); ok {
+		err = s.Stream(f, storage, r)
+	} else if err = r.Load(storage); err == nil {
+		err = e.Write(f, r)
+	}
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("%s export: %w", e.Name(), err)
+	}
+	return f.Close()
+}
+
+// registry holds the exporters in the order they were registered
+var registry struct {
+	sync.RWMutex
+	exporters []Exporter
+}
+
+func init() {
+	for _, e := range []Exporter{CSV, CSVByDay, PDF, XLSX, ICS, JSON, NDJSON} {
+		Register(e)
+	}
+	for _, t := range BuiltinTemplates() {
+		Register(t)
+	}
+}
+
+// Register adds an exporter, replacing any registered under the same name
+func Register(e Exporter) {
+	registry.Lock()
+	defer registry.Unlock()
+	for i, existing := range registry.exporters {
+		if existing.Name() == e.Name() {
+			registry.exporters[i] = e
+			return
+		}
+	}
+	registry.exporters = append(registry.exporters, e)
+}
+
+// Unregister removes the exporter with the given name
+func Unregister(name string) {
+	registry.Lock()
+	defer registry.Unlock()
+	for i, e := range registry.exporters {
+		if e.Name() == name {
+			registry.exporters = append(registry.exporters[:i], registry.exporters[i+1:]...)
+			return
+		}
+	}
+}
+
+// Exporters returns the registered exporters in registration order
+func Exporters() []Exporter {
+	registry.RLock()
+	defer registry.RUnlock()
+	return append([]Exporter(nil), registry.exporters...)
+}
+
+// Lookup returns the exporter with the given name, or nil
+func Lookup(name string) Exporter {
+	registry.RLock()
+	defer registry.RUnlock()
+	for _, e := range registry.exporters {
+		if e.Name() == name {
+			return e
+		}
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"katana/ical"
+	"katana/tracker"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// icsProdID identifies Katana in exported calendars
+const icsProdID = "-//Katana//Time Tracker//EN"
+
+// Non-standard properties that carry the session fields a calendar has no
+// place for, so an exported file imports back unchanged
+const (
+	icsCategory = "X-KATANA-CATEGORY"
+	icsProject  = "X-KATANA-PROJECT"
+	icsTracked  = "X-KATANA-TRACKED" // Tracked seconds, less than the span when paused
+	icsBillable = "X-KATANA-BILLABLE"
+	icsPomodoro = "X-KATANA-POMODORO"
+)
+
+// SessionUID is the stable calendar UID of a stored session
+func SessionUID(s *tracker.Session) string {
+	return fmt.Sprintf("session-%d@katana", s.ID)
+}
+
+// SessionEvent converts a session to a calendar event. The summary is the
+// activity, the categories are the session's category followed by its tags
+// and the notes become the description.
+func SessionEvent(s *tracker.Session) ical.Event {
+	ev := ical.Event{
+		UID:         SessionUID(s),
+		Summary:     s.Activity,
+		Description: s.Notes,
+		Start:       s.StartTime,
+		End:         s.EndTime,
+		Properties: map[string]string{
+			icsTracked: strconv.FormatInt(int64(s.Duration/time.Second), 10),
+		},
+	}
+	if s.Category != "" {
+		ev.Categories = append(ev.Categories, s.Category)
+		ev.Properties[icsCategory] = s.Category
+	}
+	ev.Categories = append(ev.Categories, s.Tags...)
+	if s.Project != "" {
+		ev.Properties[icsProject] = s.Project
+	}
+	if s.Billable {
+		ev.Properties[icsBillable] = "TRUE"
+	}
+	if s.Pomodoro {
+		ev.Properties[icsPomodoro] = "TRUE"
+	}
+	return ev
+}
+
+// WriteICS writes sessions as an iCalendar stream of VEVENTs
+func WriteICS(w io.Writer, sessions []*tracker.Session) error {
+	events := make([]ical.Event, len(sessions))
+	for i, s := range sessions {
+		events[i] = SessionEvent(s)
+	}
+	return ical.Write(w, icsProdID, events)
+}
+
+// ICS writes the report's sessions as calendar events. Sessions are kept
+// whole so each keeps a single UID.
+var ICS Exporter = icsExporter{}
+
+type icsExporter struct{}
+
+func (icsExporter) Name() string                      { return "iCalendar" }
+func (icsExporter) Extension() string                 { return "ics" }
+func (icsExporter) MIME() string                      { return "text/calendar" }
+func (icsExporter) Write(w io.Writer, r Report) error { return WriteICS(w, r.Sessions) }
+
+// SessionFromEvent converts a calendar event to an unsaved session. Events
+// exported by Katana keep all their fields; other events are read like the
+// activity entry, e.g. "study:math #exam @thesis", taking the first
+// category when the summary names none.
+func SessionFromEvent(ev ical.Event) *tracker.Session {
+	if _, ok := ev.Properties[icsTracked]; !ok {
+		s := tracker.NewSessionAt(ev.Summary, ev.Start, ev.End)
+		if s.Category == "" && len(ev.Categories) > 0 {
+			s.Category = ev.Categories[0]
+		}
+		s.Notes = ev.Description
+		return s
+	}
+
+	s := &tracker.Session{
+		StartTime: ev.Start,
+		EndTime:   ev.End,
+		Duration:  ev.End.Sub(ev.Start),
+		Activity:  ev.Summary,
+		Category:  ev.Properties[icsCategory],
+		Project:   ev.Properties[icsProject],
+		Billable:  strings.EqualFold(ev.Properties[icsBillable], "TRUE"),
+		Pomodoro:  strings.EqualFold(ev.Properties[icsPomodoro], "TRUE"),
+		Notes:     ev.Description,
+	}
+	if secs, err := strconv.ParseInt(ev.Properties[icsTracked], 10, 64); err == nil && secs >= 0 {
+		s.Duration = time.Duration(secs) * time.Second
+		s.PausedDuration = ev.End.Sub(ev.Start) - s.Duration
+	}
+	tags := ev.Categories
+	if s.Category != "" && len(tags) > 0 && tags[0] == s.Category {
+		tags = tags[1:]
+	}
+	if len(tags) > 0 {
+		s.Tags = append([]string(nil), tags...)
+	}
+	return s
+}
+
+// PlannedBlockFromEvent converts a calendar event to an unsaved planned
+// block, keeping the event's UID so importing again updates the block
+func PlannedBlockFromEvent(ev ical.Event) tracker.PlannedBlock {
+	b := tracker.NewPlannedBlock(ev.Summary, ev.Start, ev.End)
+	if category, ok := ev.Properties[icsCategory]; ok {
+		b.Activity = ev.Summary
+		b.Category = category
+		b.Project = ev.Properties[icsProject]
+	} else if b.Category == "" && len(ev.Categories) > 0 {
+		b.Category = ev.Categories[0]
+	}
+	b.UID = ev.UID
+	return b
+}
+
+// ReadICS parses the timed events of an iCalendar stream; all-day events
+// are skipped since they have no tracked time
+func ReadICS(r io.Reader) ([]ical.Event, error) {
+	events, err := ical.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+	var timed []ical.Event
+	for _, ev := range events {
+		if !ev.AllDay && ev.End.After(ev.Start) {
+			timed = append(timed, ev)
+		}
+	}
+	if len(timed) == 0 {
+		return nil, fmt.Errorf("no timed events found in the calendar")
+	}
+	return timed, nil
+}
@@ -0,0 +1,187 @@
+package export
+
+import (
+	"fmt"
+	"katana/tracker"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// Invoice holds everything printed on an invoice
+type Invoice struct {
+	Number      string
+	IssueDate   time.Time
+	DueDate     time.Time
+	PeriodStart time.Time
+	PeriodEnd   time.Time // Exclusive
+	Issuer      string    // Sender name and address, one line per row
+	Client      tracker.Client
+	Lines       []tracker.BillLine
+	TaxPercent  float64
+}
+
+// ProjectSubtotal is the billed time and amount for one project on an invoice
+type ProjectSubtotal struct {
+	Project string
+	Billed  time.Duration
+	Amount  float64
+}
+
+// Subtotals returns per-project totals in the order projects first appear
+func (inv Invoice) Subtotals() []ProjectSubtotal {
+	var subtotals []ProjectSubtotal
+	index := make(map[string]int)
+	for _, l := range inv.Lines {
+		i, ok := index[l.Project]
+		if !ok {
+			i = len(subtotals)
+			index[l.Project] = i
+			subtotals = append(subtotals, ProjectSubtotal{Project: l.Project})
+		}
+		subtotals[i].Billed += l.Billed
+		subtotals[i].Amount += l.Amount()
+	}
+	return subtotals
+}
+
+// Subtotal returns the invoice total before tax
+func (inv Invoice) Subtotal() float64 {
+	total := 0.0
+	for _, l := range inv.Lines {
+		total += l.Amount()
+	}
+	return roundCents(total)
+}
+
+// Tax returns the tax amount
+func (inv Invoice) Tax() float64 {
+	return roundCents(inv.Subtotal() * inv.TaxPercent / 100)
+}
+
+// Total returns the amount due
+func (inv Invoice) Total() float64 {
+	return inv.Subtotal() + inv.Tax()
+}
+
+// ExportInvoicePDF writes the invoice as a PDF with line items grouped by project
+func ExportInvoicePDF(inv Invoice, filename string) error {
+	if len(inv.Lines) == 0 {
+		return fmt.Errorf("invoice %s has no billable time", inv.Number)
+	}
+	currency := inv.Client.Currency
+	money := func(v float64) string {
+		return fmt.Sprintf("%.2f %s", v, currency)
+	}
+
+	pdf := newPDF()
+	pdf.SetAutoPageBreak(true, 20)
+	pdf.AddPage()
+
+	// Header: sender on the left, invoice details on the right
+	pdf.SetFont(pdfFont, "B", 20)
+	pdf.Cell(0, 12, "INVOICE")
+	pdf.Ln(14)
+	pdf.SetFont(pdfFont, "", 10)
+	top := pdf.GetY()
+	for _, line := range strings.Split(inv.Issuer, "\n") {
+		pdf.Cell(100, 5, line)
+		pdf.Ln(5)
+	}
+	pdf.SetXY(120, top)
+	for _, row := range [][2]string{
+		{"Invoice no.", inv.Number},
+		{"Issued", inv.IssueDate.Format("2006-01-02")},
+		{"Due", inv.DueDate.Format("2006-01-02")},
+		{"Period", inv.PeriodStart.Format("2006-01-02") + " - " + inv.PeriodEnd.AddDate(0, 0, -1).Format("2006-01-02")},
+	} {
+		pdf.SetX(120)
+		pdf.CellFormat(30, 5, row[0], "", 0, "L", false, 0, "")
+		pdf.CellFormat(50, 5, row[1], "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(8)
+
+	// Bill to
+	pdf.SetFont(pdfFont, "B", 11)
+	pdf.Cell(0, 6, "Bill to")
+	pdf.Ln(6)
+	pdf.SetFont(pdfFont, "", 10)
+	pdf.Cell(0, 5, inv.Client.Name)
+	pdf.Ln(5)
+	for _, line := range strings.Split(inv.Client.Address, "\n") {
+		if line != "" {
+			pdf.Cell(0, 5, line)
+			pdf.Ln(5)
+		}
+	}
+	pdf.Ln(6)
+
+	// Line items, grouped by project
+	widths := []float64{25, 85, 20, 25, 35}
+	header := []string{"Date", "Description", "Hours", "Rate", "Amount"}
+	pdf.SetFont(pdfFont, "B", 10)
+	pdf.SetFillColor(230, 230, 230)
+	for i, h := range header {
+		align := "L"
+		if i >= 2 {
+			align = "R"
+		}
+		pdf.CellFormat(widths[i], 7, h, "B", 0, align, true, 0, "")
+	}
+	pdf.Ln(-1)
+
+	for _, sub := range inv.Subtotals() {
+		pdf.SetFont(pdfFont, "B", 10)
+		pdf.CellFormat(0, 7, sub.Project, "", 1, "L", false, 0, "")
+		pdf.SetFont(pdfFont, "", 9)
+		for _, l := range inv.Lines {
+			if l.Project != sub.Project {
+				continue
+			}
+			pdf.CellFormat(widths[0], 6, l.Date.Format("2006-01-02"), "", 0, "L", false, 0, "")
+			pdf.CellFormat(widths[1], 6, fitText(pdf, l.Description, widths[1]-2), "", 0, "L", false, 0, "")
+			pdf.CellFormat(widths[2], 6, fmt.Sprintf("%.2f", l.Billed.Hours()), "", 0, "R", false, 0, "")
+			pdf.CellFormat(widths[3], 6, fmt.Sprintf("%.2f", l.Rate), "", 0, "R", false, 0, "")
+			pdf.CellFormat(widths[4], 6, money(l.Amount()), "", 1, "R", false, 0, "")
+		}
+		pdf.SetFont(pdfFont, "I", 9)
+		pdf.CellFormat(widths[0]+widths[1], 6, "Subtotal "+sub.Project, "T", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[2], 6, fmt.Sprintf("%.2f", sub.Billed.Hours()), "T", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[3], 6, "", "T", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[4], 6, money(sub.Amount), "T", 1, "R", false, 0, "")
+		pdf.Ln(2)
+	}
+
+	// Totals
+	pdf.Ln(4)
+	labelWidth := widths[0] + widths[1] + widths[2] + widths[3]
+	pdf.SetFont(pdfFont, "", 10)
+	pdf.CellFormat(labelWidth, 6, "Subtotal", "", 0, "R", false, 0, "")
+	pdf.CellFormat(widths[4], 6, money(inv.Subtotal()), "", 1, "R", false, 0, "")
+	pdf.CellFormat(labelWidth, 6, fmt.Sprintf("Tax (%g%%)", inv.TaxPercent), "", 0, "R", false, 0, "")
+	pdf.CellFormat(widths[4], 6, money(inv.Tax()), "", 1, "R", false, 0, "")
+	pdf.SetFont(pdfFont, "B", 11)
+	pdf.CellFormat(labelWidth, 8, "Total due", "T", 0, "R", false, 0, "")
+	pdf.CellFormat(widths[4], 8, money(inv.Total()), "T", 1, "R", false, 0, "")
+
+	return pdf.OutputFileAndClose(filename)
+}
+
+// fitText shortens s with an ellipsis so it fits in width at the current font
+func fitText(pdf *gofpdf.Fpdf, s string, width float64) string {
+	if pdf.GetStringWidth(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && pdf.GetStringWidth(string(runes)+"...") > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
+
+// roundCents rounds a money amount to two decimals
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,209 @@
+package export
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"katana/tracker"
+	"time"
+)
+
+// JSON export schema. The version is raised whenever a field is renamed,
+// removed or changes meaning; new optional fields keep the version.
+// See docs/guides/EXPORT_JSON_SCHEMA.md.
+const (
+	JSONSchema        = "katana.sessions"
+	JSONSchemaVersion = 1
+)
+
+// JSONSession is one session in the JSON and NDJSON exports
+type JSONSession struct {
+	ID              int64    `json:"id"`
+	Start           string   `json:"start"` // RFC 3339 with offset
+	End             string   `json:"end"`
+	DurationSeconds int64    `json:"duration_seconds"` // Tracked time, excluding pauses
+	Activity        string   `json:"activity"`
+	Category        string   `json:"category"`
+	Tags            []string `json:"tags"` // Never null
+	Project         string   `json:"project,omitempty"`
+	Billable        bool     `json:"billable"`
+	Pomodoro        bool     `json:"pomodoro"`
+	Notes           string   `json:"notes,omitempty"`
+}
+
+// NewJSONSession converts a session to its exported form
+func NewJSONSession(s *tracker.Session) JSONSession {
+	tags := s.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	return JSONSession{
+		ID:              s.ID,
+		Start:           s.StartTime.Format(time.RFC3339),
+		End:             s.EndTime.Format(time.RFC3339),
+		DurationSeconds: int64(s.Duration / time.Second),
+		Activity:        s.Activity,
+		Category:        s.Category,
+		Tags:            tags,
+		Project:         s.Project,
+		Billable:        s.Billable,
+		Pomodoro:        s.Pomodoro,
+		Notes:           s.Notes,
+	}
+}
+
+// jsonHeader opens a JSON document; the sessions array follows it
+type jsonHeader struct {
+	Schema     string `json:"schema"`
+	Version    int    `json:"version"`
+	ExportedAt string `json:"exported_at"`
+	RangeStart string `json:"range_start,omitempty"`
+	RangeEnd   string `json:"range_end,omitempty"` // Exclusive
+}
+
+// ndjsonSession is one NDJSON line: a session tagged with the schema version
+type ndjsonSession struct {
+	Version int `json:"version"`
+	JSONSession
+}
+
+// SessionStream is the storage needed by the streaming exports
+type SessionStream interface {
+	EachSessionInRange(start, end time.Time, fn func(*tracker.Session) error) error
+}
+
+// JSONWriter streams sessions as a JSON document or as NDJSON, one session
+// at a time. Close must be called to finish the document.
+type JSONWriter struct {
+	w      *bufio.Writer
+	ndjson bool
+	count  int
+	err    error
+}
+
+// NewJSONWriter starts a JSON document of the form
+// {"schema":..., "version":..., "exported_at":..., "sessions":[...]}.
+// A zero start or end leaves the range out of the header.
+func NewJSONWriter(w io.Writer, start, end time.Time) *JSONWriter {
+	jw := &JSONWriter{w: bufio.NewWriter(w)}
+	header := jsonHeader{Schema: JSONSchema, Version: JSONSchemaVersion, ExportedAt: time.Now().Format(time.RFC3339)}
+	if !start.IsZero() && !end.IsZero() {
+		header.RangeStart, header.RangeEnd = start.Format(time.RFC3339), end.Format(time.RFC3339)
+	}
+	b, err := json.Marshal(header)
+	if err != nil {
+		jw.err = err
+		return jw
+	}
+	// Reopen the header object to append the sessions array
+	jw.w.Write(b[:len(b)-1])
+	_, jw.err = jw.w.WriteString(`,"sessions":[` + "\n")
+	return jw
+}
+
+// NewNDJSONWriter writes one JSON object per line, each carrying the schema version
+func NewNDJSONWriter(w io.Writer) *JSONWriter {
+	return &JSONWriter{w: bufio.NewWriter(w), ndjson: true}
+}
+
+// Write appends a session
+func (jw *JSONWriter) Write(s *tracker.Session) error {
+	if jw.err != nil {
+		return jw.err
+	}
+	var b []byte
+	if jw.ndjson {
+		b, jw.err = json.Marshal(ndjsonSession{Version: JSONSchemaVersion, JSONSession: NewJSONSession(s)})
+	} else {
+		b, jw.err = json.Marshal(NewJSONSession(s))
+		if jw.err == nil && jw.count > 0 {
+			jw.w.WriteString(",\n")
+		}
+	}
+	if jw.err != nil {
+		return jw.err
+	}
+	jw.w.Write(b)
+	if jw.ndjson {
+		jw.w.WriteByte('\n')
+	}
+	jw.count++
+	return nil
+}
+
+// Close finishes the document and flushes it
+func (jw *JSONWriter) Close() error {
+	if jw.err != nil {
+		return jw.err
+	}
+	if !jw.ndjson {
+		jw.w.WriteString("\n]}\n")
+	}
+	return jw.w.Flush()
+}
+
+// JSON and NDJSON write the report's sessions whole, streaming them from
+// storage when exporting to a file
+var (
+	JSON   Exporter = jsonExporter{}
+	NDJSON Exporter = jsonExporter{ndjson: true}
+)
+
+type jsonExporter struct {
+	ndjson bool
+}
+
+func (e jsonExporter) Name() string {
+	if e.ndjson {
+		return "NDJSON"
+	}
+	return "JSON"
+}
+
+func (e jsonExporter) Extension() string {
+	if e.ndjson {
+		return "ndjson"
+	}
+	return "json"
+}
+
+func (e jsonExporter) MIME() string {
+	if e.ndjson {
+		return "application/x-ndjson"
+	}
+	return "application/json"
+}
+
+// open starts a document on w
+func (e jsonExporter) open(w io.Writer, r Report) *JSONWriter {
+	if e.ndjson {
+		return NewNDJSONWriter(w)
+	}
+	return NewJSONWriter(w, r.Start, r.End)
+}
+
+func (e jsonExporter) Write(w io.Writer, r Report) error {
+	jw := e.open(w, r)
+	for _, s := range r.Sessions {
+		if err := jw.Write(s); err != nil {
+			return err
+		}
+	}
+	return jw.Close()
+}
+
+// Stream writes the filtered sessions of the range as they are read
+func (e jsonExporter) Stream(w io.Writer, storage SessionStream, r Report) error {
+	jw := e.open(w, r)
+	err := storage.EachSessionInRange(r.Start, r.End, func(s *tracker.Session) error {
+		if !r.Filter.Matches(s) {
+			return nil
+		}
+		return jw.Write(s)
+	})
+	if err != nil {
+		return fmt.Errorf("export stopped: %w", err)
+	}
+	return jw.Close()
+}
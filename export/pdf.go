@@ -0,0 +1,457 @@
+package export
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"katana/tracker"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// Noto Sans covers Latin, Greek and Cyrillic; see fonts/OFL.txt for its license.
+// PDF reports and invoices embed no fallback font, so other scripts such as
+// Chinese, Japanese, Korean, Arabic and Hebrew print as missing glyphs.
+var (
+	//go:embed fonts/NotoSans-Regular.ttf
+	notoSansRegular []byte
+	//go:embed fonts/NotoSans-Bold.ttf
+	notoSansBold []byte
+	//go:embed fonts/NotoSans-Italic.ttf
+	notoSansItalic []byte
+)
+
+const (
+	pdfFont       = "NotoSans"
+	pdfLineHeight = 4.6 // mm per wrapped line in tables
+	pdfCellPad    = 1.2 // mm of padding around table cells
+	pdfChartH     = 45  // mm
+)
+
+// pdfColumnWeights sets the relative widths of the table columns
+var pdfColumnWeights = map[Column]float64{
+	ColumnDate: 20, ColumnStart: 13, ColumnEnd: 13, ColumnDuration: 16, ColumnActivity: 38,
+	ColumnCategory: 24, ColumnTags: 26, ColumnProject: 22, ColumnBillable: 14, ColumnNotes: 48,
+}
+
+// PDFReport is a paginated PDF report of sessions: a header and footer with
+// page numbers on every page, a chart of daily hours, one table per day
+// with a subtotal, totals per category and goal attainment
+type PDFReport struct {
+	Title    string
+	Subtitle string    // Optional second line, e.g. the filter
+	Start    time.Time // First day of the report
+	End      time.Time // Exclusive; the chart covers Start to End
+	Sessions []*tracker.Session
+	Columns  []Column // Table columns; the date is shown in each day's heading
+	Goals    []tracker.GoalProgress
+}
+
+// Write renders the report to w
+func (r PDFReport) Write(w io.Writer) error {
+	pdf := newPDF()
+	pdf.SetMargins(15, 22, 15)
+	pdf.SetAutoPageBreak(true, 18)
+	pdf.AliasNbPages("{nb}")
+
+	generated := time.Now().Format("2006-01-02 15:04")
+	pdf.SetHeaderFunc(func() {
+		pdf.SetFont(pdfFont, "B", 9)
+		pdf.SetTextColor(90, 90, 90)
+		pdf.SetXY(15, 10)
+		pdf.CellFormat(90, 5, r.Title, "", 0, "L", false, 0, "")
+		pdf.CellFormat(0, 5, r.periodLabel(), "", 1, "R", false, 0, "")
+		pdf.SetDrawColor(180, 180, 180)
+		pdf.Line(15, 16, 195, 16)
+		pdf.SetTextColor(0, 0, 0)
+		pdf.SetY(22)
+	})
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-14)
+		pdf.SetFont(pdfFont, "", 8)
+		pdf.SetTextColor(120, 120, 120)
+		pdf.CellFormat(90, 5, "Katana Time Tracker · "+generated, "", 0, "L", false, 0, "")
+		pdf.CellFormat(0, 5, fmt.Sprintf("Page %d of {nb}", pdf.PageNo()), "", 0, "R", false, 0, "")
+		pdf.SetTextColor(0, 0, 0)
+	})
+	pdf.AddPage()
+
+	pdf.SetFont(pdfFont, "B", 16)
+	pdf.MultiCell(0, 8, r.Title, "", "L", false)
+	if r.Subtitle != "" {
+		pdf.SetFont(pdfFont, "I", 10)
+		pdf.MultiCell(0, 5, r.Subtitle, "", "L", false)
+	}
+	var total time.Duration
+	for _, s := range r.Sessions {
+		total += s.Duration
+	}
+	pdf.SetFont(pdfFont, "", 10)
+	pdf.MultiCell(0, 6, fmt.Sprintf("%s | %d sessions | %s tracked", r.periodLabel(), len(r.Sessions), pdfDuration(total)), "", "L", false)
+	pdf.Ln(3)
+
+	days := r.days()
+	if len(days) > 1 {
+		r.writeChart(pdf, days)
+	}
+	r.writeDays(pdf)
+	r.writeCategories(pdf, total)
+	r.writeGoals(pdf)
+
+	pdf.Ln(2)
+	pdf.SetFont(pdfFont, "B", 12)
+	pdf.CellFormat(0, 8, fmt.Sprintf("Total: %s (%.2f hours)", pdfDuration(total), total.Hours()), "T", 1, "L", false, 0, "")
+
+	if err := pdf.Error(); err != nil {
+		return err
+	}
+	return pdf.Output(w)
+}
+
+// PDF writes a PDFReport of the report's sessions, cut at midnight
+var PDF Exporter = pdfExporter{}
+
+type pdfExporter struct{}
+
+func (pdfExporter) Name() string      { return "PDF" }
+func (pdfExporter) Extension() string { return "pdf" }
+func (pdfExporter) MIME() string      { return "application/pdf" }
+
+func (pdfExporter) Write(w io.Writer, r Report) error {
+	return PDFReport{
+		Title:    r.title(),
+		Subtitle: r.Filter.Label(),
+		Start:    r.Start,
+		End:      r.End,
+		Sessions: r.Parts(),
+		Columns:  r.Options.columns(),
+		Goals:    r.Options.Goals,
+	}.Write(w)
+}
+
+// newPDF starts an A4 document with the embedded UTF-8 font registered as
+// pdfFont in regular, bold and italic
+func newPDF() *gofpdf.Fpdf {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8FontFromBytes(pdfFont, "", notoSansRegular)
+	pdf.AddUTF8FontFromBytes(pdfFont, "B", notoSansBold)
+	pdf.AddUTF8FontFromBytes(pdfFont, "I", notoSansItalic)
+	return pdf
+}
+
+// periodLabel names the report's days
+func (r PDFReport) periodLabel() string {
+	if r.Start.IsZero() || r.End.IsZero() {
+		return ""
+	}
+	return rangeTitle(r.Start, r.End)
+}
+
+// days returns the tracked time of each day from Start to End
+func (r PDFReport) days() []tracker.DayTotal {
+	if r.Start.IsZero() || !r.End.After(r.Start) {
+		return nil
+	}
+	perDay := make(map[time.Time]time.Duration)
+	for _, s := range r.Sessions {
+		perDay[tracker.DayStart(s.StartTime.Local())] += s.Duration
+	}
+	var days []tracker.DayTotal
+	for day := tracker.DayStart(r.Start.Local()); day.Before(r.End); day = day.AddDate(0, 0, 1) {
+		days = append(days, tracker.DayTotal{Date: day, Total: perDay[day]})
+	}
+	return days
+}
+
+// writeChart draws a bar chart of hours per day with a labelled axis
+func (r PDFReport) writeChart(pdf *gofpdf.Fpdf, days []tracker.DayTotal) {
+	r.ensureSpace(pdf, pdfChartH+16)
+	pdf.SetFont(pdfFont, "B", 11)
+	pdf.CellFormat(0, 7, "Hours per day", "", 1, "L", false, 0, "")
+
+	var max time.Duration
+	for _, d := range days {
+		if d.Total > max {
+			max = d.Total
+		}
+	}
+	hours := math.Max(1, math.Ceil(max.Hours()))
+	left, top := 27.0, pdf.GetY()+2
+	width := 195 - left
+	slot := width / float64(len(days))
+
+	pdf.SetFont(pdfFont, "", 7)
+	pdf.SetDrawColor(200, 200, 200)
+	for _, h := range []float64{0, hours / 2, hours} {
+		y := top + pdfChartH - h/hours*pdfChartH
+		pdf.Line(left, y, left+width, y)
+		pdf.SetXY(15, y-2)
+		pdf.CellFormat(left-16, 4, fmt.Sprintf("%.1fh", h), "", 0, "R", false, 0, "")
+	}
+	pdf.SetFillColor(40, 160, 70)
+	// Label about ten days so the dates stay readable
+	every := int(math.Ceil(float64(len(days)) / 10))
+	for i, d := range days {
+		x := left + float64(i)*slot
+		if d.Total > 0 {
+			h := d.Total.Hours() / hours * pdfChartH
+			pdf.Rect(x+slot*0.15, top+pdfChartH-h, slot*0.7, h, "F")
+		}
+		if i%every == 0 {
+			pdf.SetXY(x, top+pdfChartH+1)
+			pdf.CellFormat(math.Max(slot, 12), 4, d.Date.Format("02 Jan"), "", 0, "L", false, 0, "")
+		}
+	}
+	pdf.SetY(top + pdfChartH + 8)
+}
+
+// writeDays prints one table per day that has sessions, each followed by its subtotal
+func (r PDFReport) writeDays(pdf *gofpdf.Fpdf) {
+	var columns []Column
+	for _, c := range r.Columns {
+		if c != ColumnDate {
+			columns = append(columns, c)
+		}
+	}
+	if len(columns) == 0 {
+		columns = []Column{ColumnStart, ColumnEnd, ColumnDuration, ColumnActivity}
+	}
+	widths := pdfColumnWidths(columns, 180)
+	headers := make([]string, len(columns))
+	for i, c := range columns {
+		headers[i] = c.pdfHeader()
+	}
+
+	for i := 0; i < len(r.Sessions); {
+		day := tracker.DayStart(r.Sessions[i].StartTime)
+		j := i
+		dayTotal := tracker.DayTotal{ByCategory: make(map[string]time.Duration)}
+		for ; j < len(r.Sessions) && tracker.DayStart(r.Sessions[j].StartTime).Equal(day); j++ {
+			dayTotal.Total += r.Sessions[j].Duration
+			dayTotal.ByCategory[r.Sessions[j].Category] += r.Sessions[j].Duration
+		}
+
+		r.ensureSpace(pdf, 8+3*pdfLineHeight)
+		pdf.SetFont(pdfFont, "B", 11)
+		pdf.CellFormat(0, 7, day.Format("Monday, 2 January 2006"), "", 1, "L", false, 0, "")
+		pdfTableRow(pdf, widths, headers, true)
+		for _, s := range r.Sessions[i:j] {
+			cells := make([]string, len(columns))
+			for k, c := range columns {
+				cells[k] = c.pdfValue(s)
+			}
+			if pdfRowHeight(pdf, widths, cells)+pdf.GetY() > pdfPageBottom(pdf) {
+				pdf.AddPage()
+				pdfTableRow(pdf, widths, headers, true)
+			}
+			pdfTableRow(pdf, widths, cells, false)
+		}
+		// Subtotal per category, then the day's total
+		var subtotals []string
+		for _, c := range dayTotal.Categories() {
+			name := c
+			if name == "" {
+				name = "(none)"
+			}
+			subtotals = append(subtotals, name+" "+pdfDuration(dayTotal.ByCategory[c]))
+		}
+		pdf.SetFont(pdfFont, "", 8.5)
+		pdf.MultiCell(0, 5, strings.Join(subtotals, "  ·  "), "", "R", false)
+		pdf.SetFont(pdfFont, "B", 9)
+		pdf.CellFormat(0, 6, "Day total: "+pdfDuration(dayTotal.Total), "", 1, "R", false, 0, "")
+		pdf.Ln(3)
+		i = j
+	}
+	if len(r.Sessions) == 0 {
+		pdf.SetFont(pdfFont, "I", 10)
+		pdf.CellFormat(0, 8, "No activity in this period", "", 1, "L", false, 0, "")
+	}
+}
+
+// writeCategories prints the time and share of each category
+func (r PDFReport) writeCategories(pdf *gofpdf.Fpdf, total time.Duration) {
+	if total <= 0 {
+		return
+	}
+	byCategory := make(map[string]time.Duration)
+	for _, s := range r.Sessions {
+		byCategory[s.Category] += s.Duration
+	}
+	categories := tracker.DayTotal{ByCategory: byCategory}.Categories()
+
+	widths := []float64{100, 40, 40}
+	r.ensureSpace(pdf, 8+3*pdfLineHeight)
+	pdf.SetFont(pdfFont, "B", 11)
+	pdf.CellFormat(0, 7, "Totals by category", "", 1, "L", false, 0, "")
+	pdfTableRow(pdf, widths, []string{"Category", "Time", "Share"}, true)
+	for _, c := range categories {
+		name := c
+		if name == "" {
+			name = "(none)"
+		}
+		pdfTableRow(pdf, widths, []string{name, pdfDuration(byCategory[c]),
+			fmt.Sprintf("%.0f%%", 100*float64(byCategory[c])/float64(total))}, false)
+	}
+	pdf.Ln(3)
+}
+
+// writeGoals prints goal attainment
+func (r PDFReport) writeGoals(pdf *gofpdf.Fpdf) {
+	if len(r.Goals) == 0 {
+		return
+	}
+	widths := []float64{70, 30, 30, 20, 30}
+	r.ensureSpace(pdf, 8+3*pdfLineHeight)
+	pdf.SetFont(pdfFont, "B", 11)
+	pdf.CellFormat(0, 7, "Goal attainment", "", 1, "L", false, 0, "")
+	pdfTableRow(pdf, widths, []string{"Goal", "Tracked", "Target", "Progress", "Status"}, true)
+	for _, g := range r.Goals {
+		pdfTableRow(pdf, widths, []string{
+			g.Goal.Label(),
+			fmt.Sprintf("%.1fh", g.Tracked.Hours()),
+			fmt.Sprintf("%.1fh", g.Goal.Hours),
+			fmt.Sprintf("%.0f%%", g.Fraction()*100),
+			g.Status(),
+		}, false)
+	}
+	pdf.Ln(3)
+}
+
+// ensureSpace starts a new page unless height mm fit above the bottom margin
+func (r PDFReport) ensureSpace(pdf *gofpdf.Fpdf, height float64) {
+	if pdf.GetY()+height > pdfPageBottom(pdf) {
+		pdf.AddPage()
+	}
+}
+
+// pdfPageBottom is the lowest y position content may reach
+func pdfPageBottom(pdf *gofpdf.Fpdf) float64 {
+	_, height := pdf.GetPageSize()
+	_, _, _, bottom := pdf.GetMargins()
+	return height - bottom
+}
+
+// pdfColumnWidths shares width mm between columns by their weights
+func pdfColumnWidths(columns []Column, width float64) []float64 {
+	var sum float64
+	for _, c := range columns {
+		sum += pdfColumnWeights[c]
+	}
+	widths := make([]float64, len(columns))
+	for i, c := range columns {
+		widths[i] = width * pdfColumnWeights[c] / sum
+	}
+	return widths
+}
+
+// pdfRowHeight is the height of a table row once its cells are wrapped
+func pdfRowHeight(pdf *gofpdf.Fpdf, widths []float64, cells []string) float64 {
+	pdf.SetFont(pdfFont, "", 8.5)
+	lines := 1
+	for i, text := range cells {
+		if n := len(pdfWrap(pdf, text, widths[i]-2*pdfCellPad)); n > lines {
+			lines = n
+		}
+	}
+	return float64(lines)*pdfLineHeight + 2*pdfCellPad
+}
+
+// pdfTableRow prints a row of wrapped cells with borders; header rows are
+// bold on a grey background
+func pdfTableRow(pdf *gofpdf.Fpdf, widths []float64, cells []string, header bool) {
+	height := pdfRowHeight(pdf, widths, cells)
+	if pdf.GetY()+height > pdfPageBottom(pdf) {
+		pdf.AddPage()
+	}
+	if header {
+		pdf.SetFont(pdfFont, "B", 8.5)
+		pdf.SetFillColor(225, 235, 225)
+	} else {
+		pdf.SetFont(pdfFont, "", 8.5)
+	}
+	pdf.SetDrawColor(190, 190, 190)
+	left, y := pdf.GetX(), pdf.GetY()
+	x := left
+	for i, text := range cells {
+		style := "D"
+		if header {
+			style = "FD"
+		}
+		pdf.Rect(x, y, widths[i], height, style)
+		for n, line := range pdfWrap(pdf, text, widths[i]-2*pdfCellPad) {
+			pdf.SetXY(x+pdfCellPad, y+pdfCellPad+float64(n)*pdfLineHeight)
+			pdf.CellFormat(widths[i]-2*pdfCellPad, pdfLineHeight, line, "", 0, "L", false, 0, "")
+		}
+		x += widths[i]
+	}
+	pdf.SetXY(left, y+height)
+}
+
+// pdfWrap splits text into lines that fit width mm, honouring line breaks
+// and breaking words longer than a line
+func pdfWrap(pdf *gofpdf.Fpdf, text string, width float64) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			candidate := word
+			if line != "" {
+				candidate = line + " " + word
+			}
+			if pdf.GetStringWidth(candidate) <= width {
+				line = candidate
+				continue
+			}
+			if line != "" {
+				lines = append(lines, line)
+			}
+			line = ""
+			for _, r := range word {
+				if line != "" && pdf.GetStringWidth(line+string(r)) > width {
+					lines = append(lines, line)
+					line = ""
+				}
+				line += string(r)
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// pdfHeader is a shorter column title for narrow table columns
+func (c Column) pdfHeader() string {
+	switch c {
+	case ColumnStart:
+		return "Start"
+	case ColumnEnd:
+		return "End"
+	case ColumnDuration:
+		return "Time"
+	}
+	return c.Header()
+}
+
+// pdfValue formats the column for a table cell
+func (c Column) pdfValue(s *tracker.Session) string {
+	switch c {
+	case ColumnDuration:
+		return pdfDuration(s.Duration)
+	case ColumnTags:
+		tags := make([]string, len(s.Tags))
+		for i, t := range s.Tags {
+			tags[i] = "#" + t
+		}
+		return strings.Join(tags, " ")
+	}
+	return c.Value(s)
+}
+
+// pdfDuration renders a duration as e.g. "2h 05m"
+func pdfDuration(d time.Duration) string {
+	m := int(d.Round(time.Minute).Minutes())
+	return fmt.Sprintf("%dh %02dm", m/60, m%60)
+}
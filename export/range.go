@@ -0,0 +1,206 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"katana/tracker"
+	"strings"
+	"time"
+)
+
+// RangeLoader is the storage needed by the range exports
+type RangeLoader interface {
+	LoadSessionsInRange(start, end time.Time) ([]*tracker.Session, error)
+}
+
+// Filter narrows a range export. Each list matches sessions having any of its
+// values, ignoring case; an empty list matches every session.
+type Filter struct {
+	Categories []string
+	Tags       []string
+	Projects   []string
+}
+
+// Matches reports whether a session passes every list of the filter
+func (f Filter) Matches(s *tracker.Session) bool {
+	if len(f.Categories) > 0 && !containsFold(f.Categories, s.Category) {
+		return false
+	}
+	if len(f.Projects) > 0 && !containsFold(f.Projects, s.Project) {
+		return false
+	}
+	if len(f.Tags) > 0 {
+		for _, t := range s.Tags {
+			if containsFold(f.Tags, t) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// Label describes the filter on one line, or returns "" when it matches everything
+func (f Filter) Label() string {
+	var parts []string
+	if len(f.Categories) > 0 {
+		parts = append(parts, "categories: "+strings.Join(f.Categories, ", "))
+	}
+	if len(f.Tags) > 0 {
+		parts = append(parts, "tags: "+strings.Join(f.Tags, ", "))
+	}
+	if len(f.Projects) > 0 {
+		parts = append(parts, "projects: "+strings.Join(f.Projects, ", "))
+	}
+	return strings.Join(parts, " | ")
+}
+
+func containsFold(list []string, v string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// Column is one field of a range export
+type Column string
+
+const (
+	ColumnDate     Column = "date"
+	ColumnStart    Column = "start"
+	ColumnEnd      Column = "end"
+	ColumnDuration Column = "duration"
+	ColumnActivity Column = "activity"
+	ColumnCategory Column = "category"
+	ColumnTags     Column = "tags"
+	ColumnProject  Column = "project"
+	ColumnBillable Column = "billable"
+	ColumnNotes    Column = "notes"
+)
+
+// AllColumns lists every column in export order
+var AllColumns = []Column{
+	ColumnDate, ColumnStart, ColumnEnd, ColumnDuration, ColumnActivity,
+	ColumnCategory, ColumnTags, ColumnProject, ColumnBillable, ColumnNotes,
+}
+
+// DefaultColumns are exported when no columns are chosen
+var DefaultColumns = []Column{
+	ColumnDate, ColumnStart, ColumnEnd, ColumnDuration, ColumnActivity, ColumnCategory, ColumnTags,
+}
+
+// Header is the column's title in exported files
+func (c Column) Header() string {
+	switch c {
+	case ColumnDate:
+		return "Date"
+	case ColumnStart:
+		return "Start Time"
+	case ColumnEnd:
+		return "End Time"
+	case ColumnDuration:
+		return "Duration (min)"
+	case ColumnActivity:
+		return "Activity"
+	case ColumnCategory:
+		return "Category"
+	case ColumnTags:
+		return "Tags"
+	case ColumnProject:
+		return "Project"
+	case ColumnBillable:
+		return "Billable"
+	case ColumnNotes:
+		return "Notes"
+	}
+	return string(c)
+}
+
+// Value formats the column for a session
+func (c Column) Value(s *tracker.Session) string {
+	switch c {
+	case ColumnDate:
+		return s.StartTime.Format("2006-01-02")
+	case ColumnStart:
+		return s.StartTime.Format("15:04")
+	case ColumnEnd:
+		return s.EndTime.Format("15:04")
+	case ColumnDuration:
+		return formatMinutes(s.Duration)
+	case ColumnActivity:
+		return s.Activity
+	case ColumnCategory:
+		return s.Category
+	case ColumnTags:
+		if len(s.Tags) == 0 {
+			return ""
+		}
+		return jsonTags(s.Tags)
+	case ColumnProject:
+		return s.Project
+	case ColumnBillable:
+		if s.Billable {
+			return "yes"
+		}
+		return "no"
+	case ColumnNotes:
+		return s.Notes
+	}
+	return ""
+}
+
+// columns returns the chosen columns, or the defaults plus notes when requested
+func (opts Options) columns() []Column {
+	if len(opts.Columns) > 0 {
+		return opts.Columns
+	}
+	if opts.IncludeNotes {
+		return append(append([]Column(nil), DefaultColumns...), ColumnNotes)
+	}
+	return DefaultColumns
+}
+
+// rangeTitle names a range of days given with an exclusive end
+func rangeTitle(start, end time.Time) string {
+	last := end.AddDate(0, 0, -1)
+	if !last.After(start) {
+		return start.Format("2006-01-02")
+	}
+	return start.Format("2006-01-02") + " to " + last.Format("2006-01-02")
+}
+
+// CSV writes one row per session with the chosen columns, cut at midnight,
+// followed by the total and any goal attainment rows
+var CSV Exporter = csvExporter{}
+
+type csvExporter struct{}
+
+func (csvExporter) Name() string      { return "CSV" }
+func (csvExporter) Extension() string { return "csv" }
+func (csvExporter) MIME() string      { return "text/csv" }
+
+func (csvExporter) Write(out io.Writer, r Report) error {
+	w := csv.NewWriter(out)
+	columns := r.Options.columns()
+	header := make([]string, len(columns))
+	for i, c := range columns {
+		header[i] = c.Header()
+	}
+	w.Write(header)
+	var total time.Duration
+	for _, s := range r.Parts() {
+		row := make([]string, len(columns))
+		for i, c := range columns {
+			row[i] = c.Value(s)
+		}
+		w.Write(row)
+		total += s.Duration
+	}
+	w.Write([]string{})
+	w.Write([]string{"Total (min)", formatMinutes(total)})
+	writeGoalsCSV(w, r.Options.Goals)
+	w.Flush()
+	return w.Error()
+}
@@ -0,0 +1,253 @@
+package export
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"katana/tracker"
+	"mime"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+)
+
+//go:embed templates/*.tmpl
+var builtinTemplates embed.FS
+
+// TemplateData is what export templates are executed with
+type TemplateData struct {
+	Title      string
+	Filter     string // Filter description, empty when unfiltered
+	Period     TemplatePeriod
+	Generated  time.Time
+	Sessions   []*tracker.Session // Cut at midnight and sorted by start time
+	Days       []TemplateDay      // Every day of the period, including empty ones
+	Categories []CategoryTotal    // Longest first
+	Total      time.Duration
+}
+
+// TemplatePeriod is the range of days a template export covers
+type TemplatePeriod struct {
+	Start time.Time // Midnight of the first day
+	End   time.Time // Exclusive
+	Last  time.Time // Midnight of the last day
+	Label string    // e.g. "2026-03-01 to 2026-03-31"
+}
+
+// TemplateDay is one day of a template export
+type TemplateDay struct {
+	Date       time.Time
+	Total      time.Duration
+	Sessions   []*tracker.Session
+	Categories []CategoryTotal
+}
+
+// CategoryTotal is the tracked time of a category; an empty Category means none
+type CategoryTotal struct {
+	Category string
+	Total    time.Duration
+	Share    float64 // Fraction of the enclosing total, 0 to 1
+}
+
+// NewTemplateData builds the template data model of a report
+func NewTemplateData(r Report) TemplateData {
+	start, end, sessions := r.Start, r.End, r.Parts()
+	data := TemplateData{
+		Title:     r.title(),
+		Filter:    r.Filter.Label(),
+		Period:    TemplatePeriod{Start: start, End: end, Last: end.AddDate(0, 0, -1), Label: rangeTitle(start, end)},
+		Generated: time.Now(),
+		Sessions:  sessions,
+	}
+	perDay := make(map[time.Time][]*tracker.Session)
+	for _, s := range sessions {
+		day := tracker.DayStart(s.StartTime.Local())
+		perDay[day] = append(perDay[day], s)
+		data.Total += s.Duration
+	}
+	for day := tracker.DayStart(start.Local()); day.Before(end); day = day.AddDate(0, 0, 1) {
+		td := TemplateDay{Date: day, Sessions: perDay[day]}
+		for _, s := range td.Sessions {
+			td.Total += s.Duration
+		}
+		td.Categories = categoryTotals(td.Sessions, td.Total)
+		data.Days = append(data.Days, td)
+	}
+	data.Categories = categoryTotals(sessions, data.Total)
+	return data
+}
+
+// categoryTotals sums sessions per category, longest first
+func categoryTotals(sessions []*tracker.Session, total time.Duration) []CategoryTotal {
+	day := tracker.DayTotal{ByCategory: make(map[string]time.Duration)}
+	for _, s := range sessions {
+		day.ByCategory[s.Category] += s.Duration
+	}
+	var totals []CategoryTotal
+	for _, c := range day.Categories() {
+		ct := CategoryTotal{Category: c, Total: day.ByCategory[c]}
+		if total > 0 {
+			ct.Share = float64(ct.Total) / float64(total)
+		}
+		totals = append(totals, ct)
+	}
+	return totals
+}
+
+// templateFuncs are available to every export template
+var templateFuncs = map[string]any{
+	"duration": pdfDuration,
+	"hours":    func(d time.Duration) string { return fmt.Sprintf("%.2f", d.Hours()) },
+	"date":     func(t time.Time) string { return t.Format("2006-01-02") },
+	"clock":    func(t time.Time) string { return t.Format("15:04") },
+	"weekday":  func(t time.Time) string { return t.Format("Monday") },
+	"percent":  func(f float64) string { return fmt.Sprintf("%.0f%%", f*100) },
+	"tags":     func(s *tracker.Session) string { return ColumnTags.pdfValue(s) },
+	"join":     strings.Join,
+	"category": func(c string) string {
+		if c == "" {
+			return "(none)"
+		}
+		return c
+	},
+	// cell makes text safe for a Markdown table cell
+	"cell": func(text string) string {
+		text = strings.ReplaceAll(text, "|", `\|`)
+		return strings.Join(strings.Fields(text), " ")
+	},
+}
+
+// Template is an export template. Templates whose extension is html or htm
+// are run with html/template, which escapes the data; all others with
+// text/template.
+type Template struct {
+	name      string
+	extension string
+	path      string // Empty for the built-in templates
+	source    string
+}
+
+// Name is shown in the export menus; user templates are prefixed "Template: "
+func (t Template) Name() string {
+	if t.path != "" {
+		return "Template: " + t.name
+	}
+	return t.name
+}
+
+// Extension is the extension of the exported file, without the dot
+func (t Template) Extension() string { return t.extension }
+
+// Path is the template's file, empty for the built-in templates
+func (t Template) Path() string { return t.path }
+
+// MIME is the media type of the exported file, guessed from its extension
+func (t Template) MIME() string {
+	switch t.extension {
+	case "md":
+		return "text/markdown; charset=utf-8"
+	case "html", "htm":
+		return "text/html; charset=utf-8"
+	}
+	if m := mime.TypeByExtension("." + t.extension); m != "" {
+		return m
+	}
+	return "text/plain; charset=utf-8"
+}
+
+// HTML reports whether the template produces HTML
+func (t Template) HTML() bool {
+	return t.extension == "html" || t.extension == "htm"
+}
+
+// Write renders the template with the report's data
+func (t Template) Write(w io.Writer, r Report) error {
+	return t.Execute(w, NewTemplateData(r))
+}
+
+// Execute renders the template with data to w
+func (t Template) Execute(w io.Writer, data TemplateData) error {
+	tmpl, err := t.parse()
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, data)
+}
+
+// parse compiles the template with html/template or text/template
+func (t Template) parse() (interface{ Execute(io.Writer, any) error }, error) {
+	if t.HTML() {
+		tmpl, err := htmltemplate.New(t.name).Funcs(templateFuncs).Parse(t.source)
+		if err != nil {
+			return nil, err
+		}
+		return tmpl, nil
+	}
+	tmpl, err := template.New(t.name).Funcs(templateFuncs).Parse(t.source)
+	if err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+// BuiltinTemplates returns the Markdown and HTML reports shipped with Katana
+func BuiltinTemplates() []Template {
+	md, _ := builtinTemplates.ReadFile("templates/report.md.tmpl")
+	html, _ := builtinTemplates.ReadFile("templates/report.html.tmpl")
+	return []Template{
+		{name: "Markdown", extension: "md", source: string(md)},
+		{name: "HTML", extension: "html", source: string(html)},
+	}
+}
+
+// LoadTemplates reads the user's templates in dir, which is created on
+// first use. A file named weekly.md.tmpl becomes the template "weekly"
+// writing .md files; without an inner extension the output is .txt.
+// Templates that fail to parse are left out and reported in the error.
+func LoadTemplates(dir string) ([]Template, error) {
+	paths, _ := filepath.Glob(filepath.Join(dir, "*.tmpl"))
+	if paths == nil {
+		os.MkdirAll(dir, 0755)
+	}
+	sort.Strings(paths)
+	var templates []Template
+	var errs []error
+	for _, path := range paths {
+		source, err := os.ReadFile(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		name := strings.TrimSuffix(filepath.Base(path), ".tmpl")
+		t := Template{name: name, extension: "txt", path: path, source: string(source)}
+		if ext := filepath.Ext(name); ext != "" {
+			t.name, t.extension = strings.TrimSuffix(name, ext), strings.ToLower(ext[1:])
+		}
+		if _, err := t.parse(); err != nil {
+			errs = append(errs, fmt.Errorf("invalid template %s: %w", path, err))
+			continue
+		}
+		templates = append(templates, t)
+	}
+	return templates, errors.Join(errs...)
+}
+
+// RegisterTemplates registers the user's templates in dir in place of those
+// registered before, so edited, added and removed files are picked up
+func RegisterTemplates(dir string) error {
+	for _, e := range Exporters() {
+		if t, ok := e.(Template); ok && t.path != "" {
+			Unregister(t.Name())
+		}
+	}
+	templates, err := LoadTemplates(dir)
+	for _, t := range templates {
+		Register(t)
+	}
+	return err
+}
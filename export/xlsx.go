@@ -0,0 +1,392 @@
+package export
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"katana/tracker"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cell styles, indexes into cellXfs of xlsxStyles
+const (
+	xlsxStyleDefault = iota
+	xlsxStyleHeader
+	xlsxStyleDate
+	xlsxStyleDateTime
+	xlsxStyleDuration
+	xlsxStyleHours
+	xlsxStyleTotalHours
+)
+
+const xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<numFmts count="4">
+<numFmt numFmtId="164" formatCode="yyyy-mm-dd"/>
+<numFmt numFmtId="165" formatCode="yyyy-mm-dd hh:mm"/>
+<numFmt numFmtId="166" formatCode="[h]:mm"/>
+<numFmt numFmtId="167" formatCode="0.00"/>
+</numFmts>
+<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>
+<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>
+<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>
+<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>
+<cellXfs count="7">
+<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>
+<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>
+<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>
+<xf numFmtId="165" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>
+<xf numFmtId="166" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>
+<xf numFmtId="167" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>
+<xf numFmtId="167" fontId="1" fillId="0" borderId="0" xfId="0" applyNumberFormat="1" applyFont="1"/>
+</cellXfs>
+<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>
+</styleSheet>`
+
+// xlsxCell is one cell: a string, a number or a formula
+type xlsxCell struct {
+	text    string
+	number  float64
+	formula string
+	kind    byte // 's' string, 'n' number, 'f' formula, 0 empty
+	style   int
+}
+
+func xlsxText(s string, style int) xlsxCell    { return xlsxCell{text: s, kind: 's', style: style} }
+func xlsxNumber(v float64, style int) xlsxCell { return xlsxCell{number: v, kind: 'n', style: style} }
+func xlsxFormula(f string, style int) xlsxCell { return xlsxCell{formula: f, kind: 'f', style: style} }
+
+// xlsxSheet is a worksheet as rows of cells
+type xlsxSheet struct {
+	name   string
+	widths []float64 // Column widths in characters
+	rows   [][]xlsxCell
+}
+
+// xlsxColumn returns the column letters of a zero-based index: A, B, ..., AA
+func xlsxColumn(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
+
+// xlsxSerial converts a time to a spreadsheet serial date in local wall clock time
+func xlsxSerial(t time.Time) float64 {
+	t = t.Local()
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	return wall.Sub(time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)).Hours() / 24
+}
+
+func xlsxEscape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '&':
+			b.WriteString("&amp;")
+		case '<':
+			b.WriteString("&lt;")
+		case '>':
+			b.WriteString("&gt;")
+		case '"':
+			b.WriteString("&quot;")
+		case '\t', '\n', '\r':
+			b.WriteRune(r)
+		default:
+			// Other control characters are not allowed in XML 1.0
+			if r >= 0x20 {
+				b.WriteRune(r)
+			}
+		}
+	}
+	return b.String()
+}
+
+// write encodes the worksheet XML with the first row frozen
+func (sh xlsxSheet) write(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n")
+	b.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	b.WriteString(`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>`)
+	if len(sh.widths) > 0 {
+		b.WriteString("<cols>")
+		for i, width := range sh.widths {
+			fmt.Fprintf(&b, `<col min="%d" max="%d" width="%g" customWidth="1"/>`, i+1, i+1, width)
+		}
+		b.WriteString("</cols>")
+	}
+	b.WriteString("<sheetData>")
+	for r, row := range sh.rows {
+		fmt.Fprintf(&b, `<row r="%d">`, r+1)
+		for c, cell := range row {
+			ref := fmt.Sprintf("%s%d", xlsxColumn(c), r+1)
+			switch cell.kind {
+			case 's':
+				fmt.Fprintf(&b, `<c r="%s" s="%d" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, cell.style, xlsxEscape(cell.text))
+			case 'n':
+				fmt.Fprintf(&b, `<c r="%s" s="%d"><v>%s</v></c>`, ref, cell.style, strconv.FormatFloat(cell.number, 'f', -1, 64))
+			case 'f':
+				fmt.Fprintf(&b, `<c r="%s" s="%d"><f>%s</f></c>`, ref, cell.style, xlsxEscape(cell.formula))
+			}
+		}
+		b.WriteString("</row>")
+	}
+	b.WriteString("</sheetData></worksheet>")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeXLSXWorkbook packages worksheets into an XLSX (Office Open XML) file
+func writeXLSXWorkbook(w io.Writer, sheets []xlsxSheet) error {
+	z := zip.NewWriter(w)
+	add := func(name, content string) error {
+		f, err := z.Create(name)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(f, content)
+		return err
+	}
+
+	var types, workbook, rels strings.Builder
+	types.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>
+`)
+	workbook.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets>
+`)
+	rels.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rStyles" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>
+`)
+	for i, sh := range sheets {
+		fmt.Fprintf(&types, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`+"\n", i+1)
+		fmt.Fprintf(&workbook, `<sheet name="%s" sheetId="%d" r:id="rSheet%d"/>`+"\n", xlsxEscape(sh.name), i+1, i+1)
+		fmt.Fprintf(&rels, `<Relationship Id="rSheet%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`+"\n", i+1, i+1)
+	}
+	types.WriteString("</Types>")
+	// Formulas are stored without cached values, so have them computed on open
+	workbook.WriteString("</sheets>\n<calcPr fullCalcOnLoad=\"1\"/>\n</workbook>")
+	rels.WriteString("</Relationships>")
+
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", types.String()},
+		{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`},
+		{"xl/workbook.xml", workbook.String()},
+		{"xl/_rels/workbook.xml.rels", rels.String()},
+		{"xl/styles.xml", xlsxStyles},
+	}
+	for _, p := range parts {
+		if err := add(p.name, p.content); err != nil {
+			return err
+		}
+	}
+	for i, sh := range sheets {
+		f, err := z.Create(fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1))
+		if err != nil {
+			return err
+		}
+		if err := sh.write(f); err != nil {
+			return err
+		}
+	}
+	return z.Close()
+}
+
+// WriteXLSX writes a workbook for the sessions of the days from start up to,
+// but not including, end: the sessions with date, time and duration cells,
+// hours per day by category, and a weekly timesheet per project whose totals
+// are formulas. Sessions should be cut at midnight, as Report.Parts returns them.
+func WriteXLSX(w io.Writer, sessions []*tracker.Session, start, end time.Time) error {
+	return writeXLSXWorkbook(w, []xlsxSheet{
+		xlsxSessionsSheet(sessions),
+		xlsxCategorySheet(sessions, start, end),
+		xlsxTimesheet(sessions, start, end),
+	})
+}
+
+// XLSX writes the report as a workbook, see WriteXLSX
+var XLSX Exporter = xlsxExporter{}
+
+type xlsxExporter struct{}
+
+func (xlsxExporter) Name() string      { return "XLSX" }
+func (xlsxExporter) Extension() string { return "xlsx" }
+func (xlsxExporter) MIME() string {
+	return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+}
+
+func (xlsxExporter) Write(w io.Writer, r Report) error {
+	return WriteXLSX(w, r.Parts(), r.Start, r.End)
+}
+
+func xlsxSessionsSheet(sessions []*tracker.Session) xlsxSheet {
+	sh := xlsxSheet{name: "Sessions", widths: []float64{12, 17, 17, 10, 8, 28, 16, 20, 16, 9, 40}}
+	header := []string{"Date", "Start", "End", "Duration", "Hours", "Activity", "Category", "Tags", "Project", "Billable", "Notes"}
+	row := make([]xlsxCell, len(header))
+	for i, h := range header {
+		row[i] = xlsxText(h, xlsxStyleHeader)
+	}
+	sh.rows = append(sh.rows, row)
+	for _, s := range sessions {
+		billable := "no"
+		if s.Billable {
+			billable = "yes"
+		}
+		r := len(sh.rows) + 1
+		sh.rows = append(sh.rows, []xlsxCell{
+			xlsxNumber(float64(int(xlsxSerial(s.StartTime))), xlsxStyleDate),
+			xlsxNumber(xlsxSerial(s.StartTime), xlsxStyleDateTime),
+			xlsxNumber(xlsxSerial(s.EndTime), xlsxStyleDateTime),
+			xlsxNumber(s.Duration.Hours()/24, xlsxStyleDuration),
+			xlsxFormula(fmt.Sprintf("D%d*24", r), xlsxStyleHours),
+			xlsxText(s.Activity, 0),
+			xlsxText(s.Category, 0),
+			xlsxText(strings.Join(s.Tags, ", "), 0),
+			xlsxText(s.Project, 0),
+			xlsxText(billable, 0),
+			xlsxText(s.Notes, 0),
+		})
+	}
+	last := len(sh.rows)
+	sh.rows = append(sh.rows, []xlsxCell{
+		xlsxText("Total", xlsxStyleHeader), {}, {},
+		xlsxFormula(fmt.Sprintf("SUM(D2:D%d)", max(last, 2)), xlsxStyleDuration),
+		xlsxFormula(fmt.Sprintf("SUM(E2:E%d)", max(last, 2)), xlsxStyleTotalHours),
+	})
+	return sh
+}
+
+// xlsxCategorySheet pivots hours per day (rows) by category (columns)
+func xlsxCategorySheet(sessions []*tracker.Session, start, end time.Time) xlsxSheet {
+	hours := make(map[time.Time]map[string]float64)
+	totals := make(map[string]float64)
+	for _, s := range sessions {
+		day := tracker.DayStart(s.StartTime.Local())
+		if hours[day] == nil {
+			hours[day] = make(map[string]float64)
+		}
+		hours[day][s.Category] += s.Duration.Hours()
+		totals[s.Category] += s.Duration.Hours()
+	}
+	categories := make([]string, 0, len(totals))
+	for c := range totals {
+		categories = append(categories, c)
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		if totals[categories[i]] != totals[categories[j]] {
+			return totals[categories[i]] > totals[categories[j]]
+		}
+		return categories[i] < categories[j]
+	})
+
+	sh := xlsxSheet{name: "Daily by Category", widths: []float64{12}}
+	header := []xlsxCell{xlsxText("Date", xlsxStyleHeader)}
+	for _, c := range categories {
+		name := c
+		if name == "" {
+			name = "(none)"
+		}
+		header = append(header, xlsxText(name, xlsxStyleHeader))
+		sh.widths = append(sh.widths, 14)
+	}
+	header = append(header, xlsxText("Total", xlsxStyleHeader))
+	sh.rows = append(sh.rows, header)
+
+	totalCol := xlsxColumn(len(categories))
+	for day := tracker.DayStart(start.Local()); day.Before(end); day = day.AddDate(0, 0, 1) {
+		r := len(sh.rows) + 1
+		row := []xlsxCell{xlsxNumber(xlsxSerial(day), xlsxStyleDate)}
+		for _, c := range categories {
+			row = append(row, xlsxNumber(hours[day][c], xlsxStyleHours))
+		}
+		if len(categories) > 0 {
+			row = append(row, xlsxFormula(fmt.Sprintf("SUM(B%d:%s%d)", r, totalCol, r), xlsxStyleTotalHours))
+		} else {
+			row = append(row, xlsxNumber(0, xlsxStyleTotalHours))
+		}
+		sh.rows = append(sh.rows, row)
+	}
+	sh.rows = append(sh.rows, xlsxTotalRow(len(sh.rows), len(categories)+1, 1))
+	return sh
+}
+
+// xlsxTimesheet lists hours per project for each Monday-to-Sunday week
+func xlsxTimesheet(sessions []*tracker.Session, start, end time.Time) xlsxSheet {
+	type key struct {
+		week    time.Time
+		project string
+	}
+	hours := make(map[key][7]float64)
+	for _, s := range sessions {
+		day := tracker.DayStart(s.StartTime.Local())
+		offset := (int(day.Weekday()) + 6) % 7
+		k := key{week: day.AddDate(0, 0, -offset), project: s.Project}
+		h := hours[k]
+		h[offset] += s.Duration.Hours()
+		hours[k] = h
+	}
+	keys := make([]key, 0, len(hours))
+	for k := range hours {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if !keys[i].week.Equal(keys[j].week) {
+			return keys[i].week.Before(keys[j].week)
+		}
+		return keys[i].project < keys[j].project
+	})
+
+	sh := xlsxSheet{name: "Weekly Timesheet", widths: []float64{12, 20, 8, 8, 8, 8, 8, 8, 8, 9}}
+	header := []xlsxCell{xlsxText("Week of", xlsxStyleHeader), xlsxText("Project", xlsxStyleHeader)}
+	for _, d := range []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"} {
+		header = append(header, xlsxText(d, xlsxStyleHeader))
+	}
+	header = append(header, xlsxText("Total", xlsxStyleHeader))
+	sh.rows = append(sh.rows, header)
+	for _, k := range keys {
+		r := len(sh.rows) + 1
+		project := k.project
+		if project == "" {
+			project = "(no project)"
+		}
+		row := []xlsxCell{xlsxNumber(xlsxSerial(k.week), xlsxStyleDate), xlsxText(project, 0)}
+		for _, h := range hours[k] {
+			row = append(row, xlsxNumber(h, xlsxStyleHours))
+		}
+		row = append(row, xlsxFormula(fmt.Sprintf("SUM(C%d:I%d)", r, r), xlsxStyleTotalHours))
+		sh.rows = append(sh.rows, row)
+	}
+	sh.rows = append(sh.rows, xlsxTotalRow(len(sh.rows), 8, 2))
+	return sh
+}
+
+// xlsxTotalRow sums each of count columns, starting at column first, over
+// the data rows 2 to lastRow
+func xlsxTotalRow(lastRow, count, first int) []xlsxCell {
+	row := []xlsxCell{xlsxText("Total", xlsxStyleHeader)}
+	for i := 1; i < first; i++ {
+		row = append(row, xlsxCell{})
+	}
+	for i := 0; i < count; i++ {
+		col := xlsxColumn(first + i)
+		if lastRow < 2 {
+			row = append(row, xlsxNumber(0, xlsxStyleTotalHours))
+			continue
+		}
+		row = append(row, xlsxFormula(fmt.Sprintf("SUM(%s2:%s%d)", col, col, lastRow), xlsxStyleTotalHours))
+	}
+	return row
+}
@@ -0,0 +1,262 @@
+// Package ical reads and writes calendar events in iCalendar (.ics, RFC 5545) files
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Event is a VEVENT with the properties Katana uses
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Categories  []string
+	Start       time.Time
+	End         time.Time
+	AllDay      bool
+	Properties  map[string]string // Non-standard X- properties by upper-case name
+}
+
+// property is one unfolded content line, e.g. DTSTART;TZID=Europe/Paris:20240102T090000
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// Parse reads every VEVENT from an iCalendar stream. Events without a start
+// are skipped; an event without an end or duration lasts one hour, or one
+// day when it is all-day. Subcomponents of an event, such as VALARM, are
+// skipped so their properties don't replace the event's.
+func Parse(r io.Reader) ([]Event, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+	var events []Event
+	var current *Event
+	var duration time.Duration
+	// Open components, innermost last
+	var open []string
+	for _, line := range lines {
+		p, ok := parseLine(line)
+		if !ok {
+			continue
+		}
+		inEvent := len(open) > 0 && open[len(open)-1] == "VEVENT"
+		switch {
+		case p.name == "BEGIN":
+			component := strings.ToUpper(strings.TrimSpace(p.value))
+			open = append(open, component)
+			if component == "VEVENT" {
+				current = &Event{}
+				duration = 0
+			}
+		case p.name == "END":
+			component := strings.ToUpper(strings.TrimSpace(p.value))
+			// Close up to the matching BEGIN, tolerating unbalanced input
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i] == component {
+					open = open[:i]
+					break
+				}
+			}
+			if component != "VEVENT" {
+				continue
+			}
+			if current != nil && !current.Start.IsZero() {
+				if current.End.IsZero() {
+					switch {
+					case duration > 0:
+						current.End = current.Start.Add(duration)
+					case current.AllDay:
+						current.End = current.Start.AddDate(0, 0, 1)
+					default:
+						current.End = current.Start.Add(time.Hour)
+					}
+				}
+				events = append(events, *current)
+			}
+			current = nil
+		case current == nil || !inEvent:
+			// Properties of the calendar, of other components or of an event's alarms
+		case p.name == "UID":
+			current.UID = unescape(p.value)
+		case p.name == "SUMMARY":
+			current.Summary = unescape(p.value)
+		case p.name == "DESCRIPTION":
+			current.Description = unescape(p.value)
+		case p.name == "CATEGORIES":
+			for _, c := range splitList(p.value) {
+				if c = strings.TrimSpace(unescape(c)); c != "" {
+					current.Categories = append(current.Categories, c)
+				}
+			}
+		case p.name == "DTSTART":
+			t, allDay, err := parseTime(p)
+			if err != nil {
+				return nil, fmt.Errorf("event %q: %w", current.Summary, err)
+			}
+			current.Start, current.AllDay = t, allDay
+		case p.name == "DTEND":
+			t, _, err := parseTime(p)
+			if err != nil {
+				return nil, fmt.Errorf("event %q: %w", current.Summary, err)
+			}
+			current.End = t
+		case p.name == "DURATION":
+			d, err := parseDuration(p.value)
+			if err != nil {
+				return nil, fmt.Errorf("event %q: %w", current.Summary, err)
+			}
+			duration = d
+		case strings.HasPrefix(p.name, "X-"):
+			if current.Properties == nil {
+				current.Properties = make(map[string]string)
+			}
+			current.Properties[p.name] = unescape(p.value)
+		}
+	}
+	return events, nil
+}
+
+// unfold joins continuation lines, which start with a space or tab
+func unfold(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// parseLine splits a content line into name, parameters and value
+func parseLine(line string) (property, bool) {
+	colon := -1
+	quoted := false
+	for i, r := range line {
+		if r == '"' {
+			quoted = !quoted
+		} else if r == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return property{}, false
+	}
+	parts := strings.Split(line[:colon], ";")
+	p := property{name: strings.ToUpper(parts[0]), params: make(map[string]string), value: line[colon+1:]}
+	for _, param := range parts[1:] {
+		if k, v, ok := strings.Cut(param, "="); ok {
+			p.params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+		}
+	}
+	return p, true
+}
+
+// parseTime reads a DATE or DATE-TIME value in UTC, a named zone or floating local time
+func parseTime(p property) (time.Time, bool, error) {
+	value := strings.TrimSpace(p.value)
+	if p.params["VALUE"] == "DATE" || len(value) == 8 {
+		t, err := time.ParseInLocation("20060102", value, time.Local)
+		return t, true, err
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		return t.Local(), false, err
+	}
+	loc := time.Local
+	if tzid := p.params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	return t.Local(), false, err
+}
+
+// parseDuration reads an RFC 5545 duration such as PT1H30M or P1D
+func parseDuration(value string) (time.Duration, error) {
+	v := strings.TrimPrefix(strings.TrimSpace(value), "+")
+	if !strings.HasPrefix(v, "P") {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	var d time.Duration
+	inTime := false
+	num := ""
+	for _, r := range v[1:] {
+		switch {
+		case r == 'T':
+			inTime = true
+		case r >= '0' && r <= '9':
+			num += string(r)
+		default:
+			n, err := strconv.Atoi(num)
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %q", value)
+			}
+			num = ""
+			switch {
+			case r == 'W':
+				d += time.Duration(n) * 7 * 24 * time.Hour
+			case r == 'D':
+				d += time.Duration(n) * 24 * time.Hour
+			case r == 'H' && inTime:
+				d += time.Duration(n) * time.Hour
+			case r == 'M' && inTime:
+				d += time.Duration(n) * time.Minute
+			case r == 'S' && inTime:
+				d += time.Duration(n) * time.Second
+			default:
+				return 0, fmt.Errorf("invalid duration %q", value)
+			}
+		}
+	}
+	return d, nil
+}
+
+// splitList splits a comma separated value, keeping escaped commas
+func splitList(value string) []string {
+	var items []string
+	start := 0
+	for i := 0; i < len(value); i++ {
+		if value[i] == '\\' {
+			i++
+		} else if value[i] == ',' {
+			items = append(items, value[start:i])
+			start = i + 1
+		}
+	}
+	return append(items, value[start:])
+}
+
+// unescape decodes the TEXT escapes \\, \;, \, and \n
+func unescape(value string) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] == '\\' && i+1 < len(value) {
+			i++
+			switch value[i] {
+			case 'n', 'N':
+				b.WriteByte('\n')
+			default:
+				b.WriteByte(value[i])
+			}
+			continue
+		}
+		b.WriteByte(value[i])
+	}
+	return b.String()
+}
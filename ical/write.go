@@ -0,0 +1,83 @@
+package ical
+
+import (
+	"bufio"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxLineOctets is the longest content line before folding, per RFC 5545
+const maxLineOctets = 75
+
+// Write encodes events as a VCALENDAR with the given product identifier.
+// Timed events are written in UTC and all-day events as dates; lines end in
+// CRLF and are folded at 75 octets.
+func Write(w io.Writer, prodID string, events []Event) error {
+	bw := bufio.NewWriter(w)
+	stamp := time.Now().UTC().Format("20060102T150405Z")
+	writeLine(bw, "BEGIN:VCALENDAR")
+	writeLine(bw, "VERSION:2.0")
+	writeLine(bw, "PRODID:"+prodID)
+	writeLine(bw, "CALSCALE:GREGORIAN")
+	for _, ev := range events {
+		writeLine(bw, "BEGIN:VEVENT")
+		writeLine(bw, "UID:"+escape(ev.UID))
+		writeLine(bw, "DTSTAMP:"+stamp)
+		if ev.AllDay {
+			writeLine(bw, "DTSTART;VALUE=DATE:"+ev.Start.Format("20060102"))
+			writeLine(bw, "DTEND;VALUE=DATE:"+ev.End.Format("20060102"))
+		} else {
+			writeLine(bw, "DTSTART:"+ev.Start.UTC().Format("20060102T150405Z"))
+			writeLine(bw, "DTEND:"+ev.End.UTC().Format("20060102T150405Z"))
+		}
+		writeLine(bw, "SUMMARY:"+escape(ev.Summary))
+		if ev.Description != "" {
+			writeLine(bw, "DESCRIPTION:"+escape(ev.Description))
+		}
+		if len(ev.Categories) > 0 {
+			escaped := make([]string, len(ev.Categories))
+			for i, c := range ev.Categories {
+				escaped[i] = escape(c)
+			}
+			writeLine(bw, "CATEGORIES:"+strings.Join(escaped, ","))
+		}
+		names := make([]string, 0, len(ev.Properties))
+		for name := range ev.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			writeLine(bw, strings.ToUpper(name)+":"+escape(ev.Properties[name]))
+		}
+		writeLine(bw, "END:VEVENT")
+	}
+	writeLine(bw, "END:VCALENDAR")
+	return bw.Flush()
+}
+
+// writeLine writes a content line, folding it without splitting characters
+func writeLine(w *bufio.Writer, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		w.WriteString(line[:cut])
+		w.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines lose one octet to the leading space
+		limit = maxLineOctets - 1
+	}
+	w.WriteString(line)
+	w.WriteString("\r\n")
+}
+
+// escape encodes a TEXT value, the reverse of unescape
+func escape(value string) string {
+	r := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	return r.Replace(value)
+}
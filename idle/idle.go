@@ -0,0 +1,151 @@
+// Package idle reports how long the user has been away from the keyboard and mouse
+package idle
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Source reports the time since the last user input
+type Source interface {
+	IdleTime() (time.Duration, error)
+}
+
+// NewSystemSource returns the best idle source available on this machine
+func NewSystemSource() (Source, error) {
+	switch runtime.GOOS {
+	case "linux":
+		// Prefer the X11 screensaver extension (via xprintidle) when running under X
+		if os.Getenv("DISPLAY") != "" {
+			if _, err := exec.LookPath("xprintidle"); err == nil {
+				return &X11Source{}, nil
+			}
+		}
+		if _, err := exec.LookPath("loginctl"); err == nil {
+			return &LogindSource{SessionID: os.Getenv("XDG_SESSION_ID")}, nil
+		}
+		return nil, fmt.Errorf("no idle source found (install xprintidle or systemd-logind)")
+	default:
+		return nil, fmt.Errorf("idle detection not supported on %s", runtime.GOOS)
+	}
+}
+
+// X11Source queries the X11 screensaver extension through the xprintidle tool
+type X11Source struct{}
+
+// IdleTime returns the X server's idle time
+func (s *X11Source) IdleTime() (time.Duration, error) {
+	out, err := exec.Command("xprintidle").Output()
+	if err != nil {
+		return 0, fmt.Errorf("xprintidle failed: %w", err)
+	}
+	ms, err := strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("unexpected xprintidle output %q", out)
+	}
+	return time.Duration(ms) * time.Millisecond, nil
+}
+
+// LogindSource reads the IdleHint of a systemd-logind session
+type LogindSource struct {
+	SessionID string // empty means the caller's own session
+}
+
+// IdleTime returns how long logind has considered the session idle, or zero
+// while the session is active
+func (s *LogindSource) IdleTime() (time.Duration, error) {
+	id := s.SessionID
+	if id == "" {
+		id = "auto"
+	}
+	out, err := exec.Command("loginctl", "show-session", id, "-p", "IdleHint", "-p", "IdleSinceHint").Output()
+	if err != nil {
+		return 0, fmt.Errorf("loginctl failed: %w", err)
+	}
+
+	idle := false
+	var since int64
+	for _, line := range strings.Split(string(out), "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "IdleHint":
+			idle = value == "yes"
+		case "IdleSinceHint":
+			since, _ = strconv.ParseInt(value, 10, 64)
+		}
+	}
+	if !idle || since == 0 {
+		return 0, nil
+	}
+	// IdleSinceHint is in microseconds since the Unix epoch
+	return time.Since(time.UnixMicro(since)), nil
+}
+
+// Fake is a manually controlled idle source for tests
+type Fake struct {
+	mu   sync.Mutex
+	idle time.Duration
+	err  error
+}
+
+// Set changes the idle time the fake reports
+func (f *Fake) Set(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.idle = d
+}
+
+// SetError makes the fake fail with err (nil restores normal behaviour)
+func (f *Fake) SetError(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.err = err
+}
+
+// IdleTime returns the configured idle time
+func (f *Fake) IdleTime() (time.Duration, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.idle, f.err
+}
+
+// Action is what a Monitor decides after polling its source
+type Action int
+
+const (
+	None    Action = iota
+	Pause          // The user went idle: pause the running timers
+	Resolve        // The user is back: ask what to do with the idle time
+)
+
+// Monitor decides when to pause timers and when to resolve the idle time
+type Monitor struct {
+	Source    Source
+	Threshold time.Duration
+}
+
+// Check polls the source. running and paused report whether any timers are
+// running or are paused for idleness. It returns the action to take and how
+// long the user has been idle; errors from the source are treated as no change.
+func (m *Monitor) Check(running, paused bool) (Action, time.Duration) {
+	idleFor, err := m.Source.IdleTime()
+	if err != nil {
+		return None, 0
+	}
+	switch {
+	case running && idleFor >= m.Threshold:
+		return Pause, idleFor
+	case paused && idleFor < m.Threshold:
+		return Resolve, idleFor
+	}
+	return None, idleFor
+}
@@ -0,0 +1,67 @@
+package idle
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMonitorCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		idle    time.Duration
+		err     error
+		running bool
+		paused  bool
+		want    Action
+	}{
+		{"active user", time.Minute, nil, true, false, None},
+		{"idle with running timers", 10 * time.Minute, nil, true, false, Pause},
+		{"exactly at the threshold", 5 * time.Minute, nil, true, false, Pause},
+		{"idle without timers", 10 * time.Minute, nil, false, false, None},
+		{"still away while paused", 10 * time.Minute, nil, false, true, None},
+		{"back while paused", 0, nil, false, true, Resolve},
+		{"source fails", 10 * time.Minute, errors.New("no display"), true, false, None},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &Fake{}
+			fake.Set(tt.idle)
+			fake.SetError(tt.err)
+			m := &Monitor{Source: fake, Threshold: 5 * time.Minute}
+			got, idleFor := m.Check(tt.running, tt.paused)
+			if got != tt.want {
+				t.Errorf("Check = %v, want %v", got, tt.want)
+			}
+			if got == Pause && idleFor != tt.idle {
+				t.Errorf("idle for %v, want %v", idleFor, tt.idle)
+			}
+		})
+	}
+}
+
+// TestMonitorAwayAndBack walks through leaving the keyboard and returning:
+// the timers are paused once, and the idle time is resolved on return
+func TestMonitorAwayAndBack(t *testing.T) {
+	fake := &Fake{}
+	m := &Monitor{Source: fake, Threshold: 5 * time.Minute}
+	running, paused := true, false
+	var actions []Action
+	for _, idleFor := range []time.Duration{time.Minute, 4 * time.Minute, 6 * time.Minute, 7 * time.Minute, 0, 0} {
+		fake.Set(idleFor)
+		action, _ := m.Check(running, paused)
+		switch action {
+		case Pause:
+			running, paused = false, true
+		case Resolve:
+			running, paused = true, false
+		}
+		actions = append(actions, action)
+	}
+	want := []Action{None, None, Pause, None, Resolve, None}
+	for i := range want {
+		if actions[i] != want[i] {
+			t.Fatalf("actions = %v, want %v", actions, want)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"katana/config"
 	"katana/ui"
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
@@ -19,8 +20,14 @@ func main() {
 	w.Resize(fyne.NewSize(400, 320)) // Initial size only
 	// Do not call SetFixedSize or SetMinSize, allow full dynamic resizing
 
+	// Load settings, falling back to defaults if the file is unreadable
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Printf("failed to load config, using defaults: %v", err)
+	}
+
 	// Create and set the main UI
-	mainUI, err := ui.NewMainUI()
+	mainUI, err := ui.NewMainUI(cfg)
 	if err != nil {
 		log.Fatalf("failed to initialize UI: %v", err)
 	}
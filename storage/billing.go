@@ -0,0 +1,330 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"katana/tracker"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// billingSchema creates the tables for clients, projects and issued invoices
+var billingSchema = []string{
+	`CREATE TABLE IF NOT EXISTS clients (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		currency TEXT NOT NULL,
+		hourly_rate REAL NOT NULL,
+		address TEXT NOT NULL DEFAULT ''
+	)`,
+	`CREATE TABLE IF NOT EXISTS projects (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL UNIQUE,
+		client_id INTEGER NOT NULL,
+		hourly_rate REAL NOT NULL DEFAULT 0
+	)`,
+	`CREATE TABLE IF NOT EXISTS invoices (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		number TEXT NOT NULL UNIQUE,
+		client_id INTEGER NOT NULL,
+		issued TEXT NOT NULL,
+		total REAL NOT NULL
+	)`,
+}
+
+// InvoiceRecord is an issued invoice, kept so numbers are never reused
+type InvoiceRecord struct {
+	Number   string
+	ClientID int64
+	Issued   time.Time
+	Total    float64
+}
+
+// billingData is the JSON fallback representation of all billing tables
+type billingData struct {
+	Clients  []tracker.Client
+	Projects []tracker.Project
+	Invoices []InvoiceRecord
+}
+
+// createTables runs a list of CREATE TABLE statements
+func createTables(db *sql.DB, schema []string) error {
+	for _, stmt := range schema {
+		if _, err := db.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListClients returns all clients ordered by name
+func (s *Storage) ListClients() ([]tracker.Client, error) {
+	if !s.useSQLite {
+		return s.readBilling().Clients, nil
+	}
+	rows, err := s.db.Query(`SELECT id, name, currency, hourly_rate, address FROM clients ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var clients []tracker.Client
+	for rows.Next() {
+		var c tracker.Client
+		if err := rows.Scan(&c.ID, &c.Name, &c.Currency, &c.HourlyRate, &c.Address); err != nil {
+			continue
+		}
+		clients = append(clients, c)
+	}
+	return clients, nil
+}
+
+// SaveClient inserts a new client (ID 0) or updates an existing one
+func (s *Storage) SaveClient(c *tracker.Client) error {
+	if !s.useSQLite {
+		data := s.readBilling()
+		if c.ID == 0 {
+			for _, existing := range data.Clients {
+				if existing.ID > c.ID {
+					c.ID = existing.ID
+				}
+			}
+			c.ID++
+			data.Clients = append(data.Clients, *c)
+		} else {
+			found := false
+			for i := range data.Clients {
+				if data.Clients[i].ID == c.ID {
+					data.Clients[i] = *c
+					found = true
+				}
+			}
+			if !found {
+				return fmt.Errorf("client %d not found", c.ID)
+			}
+		}
+		return s.writeBilling(data)
+	}
+	if c.ID == 0 {
+		res, err := s.db.Exec(`INSERT INTO clients (name, currency, hourly_rate, address) VALUES (?, ?, ?, ?)`,
+			c.Name, c.Currency, c.HourlyRate, c.Address)
+		if err != nil {
+			return err
+		}
+		c.ID, _ = res.LastInsertId()
+		return nil
+	}
+	res, err := s.db.Exec(`UPDATE clients SET name = ?, currency = ?, hourly_rate = ?, address = ? WHERE id = ?`,
+		c.Name, c.Currency, c.HourlyRate, c.Address, c.ID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("client %d not found", c.ID)
+	}
+	return nil
+}
+
+// DeleteClient removes a client and its projects
+func (s *Storage) DeleteClient(id int64) error {
+	if !s.useSQLite {
+		data := s.readBilling()
+		var clients []tracker.Client
+		for _, c := range data.Clients {
+			if c.ID != id {
+				clients = append(clients, c)
+			}
+		}
+		var projects []tracker.Project
+		for _, p := range data.Projects {
+			if p.ClientID != id {
+				projects = append(projects, p)
+			}
+		}
+		data.Clients, data.Projects = clients, projects
+		return s.writeBilling(data)
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM projects WHERE client_id = ?`, id); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM clients WHERE id = ?`, id); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// ListProjects returns all projects ordered by name
+func (s *Storage) ListProjects() ([]tracker.Project, error) {
+	if !s.useSQLite {
+		return s.readBilling().Projects, nil
+	}
+	rows, err := s.db.Query(`SELECT id, name, client_id, hourly_rate FROM projects ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var projects []tracker.Project
+	for rows.Next() {
+		var p tracker.Project
+		if err := rows.Scan(&p.ID, &p.Name, &p.ClientID, &p.HourlyRate); err != nil {
+			continue
+		}
+		projects = append(projects, p)
+	}
+	return projects, nil
+}
+
+// SaveProject inserts a new project (ID 0) or updates an existing one
+func (s *Storage) SaveProject(p *tracker.Project) error {
+	if !s.useSQLite {
+		data := s.readBilling()
+		if p.ID == 0 {
+			for _, existing := range data.Projects {
+				if existing.Name == p.Name {
+					return fmt.Errorf("project %q already exists", p.Name)
+				}
+				if existing.ID > p.ID {
+					p.ID = existing.ID
+				}
+			}
+			p.ID++
+			data.Projects = append(data.Projects, *p)
+		} else {
+			found := false
+			for i := range data.Projects {
+				if data.Projects[i].ID == p.ID {
+					data.Projects[i] = *p
+					found = true
+				}
+			}
+			if !found {
+				return fmt.Errorf("project %d not found", p.ID)
+			}
+		}
+		return s.writeBilling(data)
+	}
+	if p.ID == 0 {
+		res, err := s.db.Exec(`INSERT INTO projects (name, client_id, hourly_rate) VALUES (?, ?, ?)`,
+			p.Name, p.ClientID, p.HourlyRate)
+		if err != nil {
+			return err
+		}
+		p.ID, _ = res.LastInsertId()
+		return nil
+	}
+	res, err := s.db.Exec(`UPDATE projects SET name = ?, client_id = ?, hourly_rate = ? WHERE id = ?`,
+		p.Name, p.ClientID, p.HourlyRate, p.ID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("project %d not found", p.ID)
+	}
+	return nil
+}
+
+// DeleteProject removes a project; sessions keep their project name
+func (s *Storage) DeleteProject(id int64) error {
+	if !s.useSQLite {
+		data := s.readBilling()
+		var projects []tracker.Project
+		for _, p := range data.Projects {
+			if p.ID != id {
+				projects = append(projects, p)
+			}
+		}
+		data.Projects = projects
+		return s.writeBilling(data)
+	}
+	_, err := s.db.Exec(`DELETE FROM projects WHERE id = ?`, id)
+	return err
+}
+
+// NextInvoiceNumber returns the next unused number of the form
+// PREFIX-YYYY-NNNN, one past the highest sequence number issued that year so
+// gaps and deleted records never lead to a number that already exists
+func (s *Storage) NextInvoiceNumber(prefix string, year int) (string, error) {
+	yearPrefix := fmt.Sprintf("%s-%d-", prefix, year)
+	var numbers []string
+	if s.useSQLite {
+		rows, err := s.db.Query(`SELECT number FROM invoices WHERE number LIKE ? ESCAPE '\'`, likeEscaper.Replace(yearPrefix)+"%")
+		if err != nil {
+			return "", err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var number string
+			if err := rows.Scan(&number); err != nil {
+				return "", err
+			}
+			numbers = append(numbers, number)
+		}
+		if err := rows.Err(); err != nil {
+			return "", err
+		}
+	} else {
+		for _, inv := range s.readBilling().Invoices {
+			numbers = append(numbers, inv.Number)
+		}
+	}
+	last := 0
+	for _, number := range numbers {
+		seq, ok := strings.CutPrefix(number, yearPrefix)
+		if !ok {
+			continue
+		}
+		if n, err := strconv.Atoi(seq); err == nil && n > last {
+			last = n
+		}
+	}
+	return fmt.Sprintf("%s%04d", yearPrefix, last+1), nil
+}
+
+// RecordInvoice stores an issued invoice so its number is not handed out again
+func (s *Storage) RecordInvoice(inv InvoiceRecord) error {
+	if !s.useSQLite {
+		data := s.readBilling()
+		for _, existing := range data.Invoices {
+			if existing.Number == inv.Number {
+				return fmt.Errorf("invoice %s already exists", inv.Number)
+			}
+		}
+		data.Invoices = append(data.Invoices, inv)
+		return s.writeBilling(data)
+	}
+	_, err := s.db.Exec(`INSERT INTO invoices (number, client_id, issued, total) VALUES (?, ?, ?, ?)`,
+		inv.Number, inv.ClientID, inv.Issued.Format(time.RFC3339), inv.Total)
+	return err
+}
+
+// billingPath is the JSON fallback file for billing data
+func (s *Storage) billingPath() string {
+	return filepath.Join(filepath.Dir(s.jsonPath), "billing.json")
+}
+
+// readBilling loads the JSON fallback billing data, empty if the file is missing
+func (s *Storage) readBilling() billingData {
+	var data billingData
+	if b, err := os.ReadFile(s.billingPath()); err == nil {
+		json.Unmarshal(b, &data)
+	}
+	return data
+}
+
+// writeBilling replaces the JSON fallback billing data
+func (s *Storage) writeBilling(data billingData) error {
+	b, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.billingPath(), b, 0644)
+}
@@ -0,0 +1,151 @@
+package storage
+
+import (
+	"encoding/json"
+	"katana/tracker"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// planSchema creates the table of planned time blocks
+var planSchema = []string{
+	`CREATE TABLE IF NOT EXISTS planned_blocks (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		start_time TEXT NOT NULL,
+		end_time TEXT NOT NULL,
+		activity TEXT NOT NULL,
+		category TEXT NOT NULL DEFAULT '',
+		project TEXT NOT NULL DEFAULT '',
+		uid TEXT NOT NULL DEFAULT ''
+	)`,
+}
+
+// LoadPlannedBlocks returns the blocks overlapping [start, end), ordered by start time
+func (s *Storage) LoadPlannedBlocks(start, end time.Time) ([]tracker.PlannedBlock, error) {
+	if !s.useSQLite {
+		var blocks []tracker.PlannedBlock
+		for _, b := range s.readPlan() {
+			if b.Start.Before(end) && b.End.After(start) {
+				blocks = append(blocks, b)
+			}
+		}
+		sort.Slice(blocks, func(i, j int) bool { return blocks[i].Start.Before(blocks[j].Start) })
+		return blocks, nil
+	}
+	rows, err := s.db.Query(`SELECT id, start_time, end_time, activity, category, project, uid FROM planned_blocks
+		WHERE start_time < ? AND end_time > ? ORDER BY start_time ASC`, end.Format(time.RFC3339), start.Format(time.RFC3339))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var blocks []tracker.PlannedBlock
+	for rows.Next() {
+		var b tracker.PlannedBlock
+		var startStr, endStr string
+		if err := rows.Scan(&b.ID, &startStr, &endStr, &b.Activity, &b.Category, &b.Project, &b.UID); err != nil {
+			continue
+		}
+		b.Start, _ = time.Parse(time.RFC3339, startStr)
+		b.End, _ = time.Parse(time.RFC3339, endStr)
+		blocks = append(blocks, b)
+	}
+	return blocks, nil
+}
+
+// SavePlannedBlocks inserts new blocks (ID 0) and updates existing ones. A
+// new block with the UID of a stored one replaces it, so importing the same
+// calendar twice does not duplicate its events.
+func (s *Storage) SavePlannedBlocks(blocks []*tracker.PlannedBlock) error {
+	if !s.useSQLite {
+		all := s.readPlan()
+		for _, b := range blocks {
+			if b.ID == 0 && b.UID != "" {
+				for _, existing := range all {
+					if existing.UID == b.UID {
+						b.ID = existing.ID
+					}
+				}
+			}
+			if b.ID == 0 {
+				for _, existing := range all {
+					if existing.ID > b.ID {
+						b.ID = existing.ID
+					}
+				}
+				b.ID++
+				all = append(all, *b)
+				continue
+			}
+			for i := range all {
+				if all[i].ID == b.ID {
+					all[i] = *b
+				}
+			}
+		}
+		return s.writePlan(all)
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	for _, b := range blocks {
+		if b.ID == 0 && b.UID != "" {
+			tx.QueryRow(`SELECT id FROM planned_blocks WHERE uid = ?`, b.UID).Scan(&b.ID)
+		}
+		if b.ID == 0 {
+			res, err := tx.Exec(`INSERT INTO planned_blocks (start_time, end_time, activity, category, project, uid) VALUES (?, ?, ?, ?, ?, ?)`,
+				b.Start.Format(time.RFC3339), b.End.Format(time.RFC3339), b.Activity, b.Category, b.Project, b.UID)
+			if err != nil {
+				tx.Rollback()
+				return err
+			}
+			b.ID, _ = res.LastInsertId()
+			continue
+		}
+		_, err := tx.Exec(`UPDATE planned_blocks SET start_time = ?, end_time = ?, activity = ?, category = ?, project = ?, uid = ? WHERE id = ?`,
+			b.Start.Format(time.RFC3339), b.End.Format(time.RFC3339), b.Activity, b.Category, b.Project, b.UID, b.ID)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// DeletePlannedBlock removes a planned block
+func (s *Storage) DeletePlannedBlock(id int64) error {
+	if !s.useSQLite {
+		var kept []tracker.PlannedBlock
+		for _, b := range s.readPlan() {
+			if b.ID != id {
+				kept = append(kept, b)
+			}
+		}
+		return s.writePlan(kept)
+	}
+	_, err := s.db.Exec(`DELETE FROM planned_blocks WHERE id = ?`, id)
+	return err
+}
+
+// planPath is the JSON fallback file for planned blocks
+func (s *Storage) planPath() string {
+	return filepath.Join(filepath.Dir(s.jsonPath), "plan.json")
+}
+
+func (s *Storage) readPlan() []tracker.PlannedBlock {
+	var blocks []tracker.PlannedBlock
+	if b, err := os.ReadFile(s.planPath()); err == nil {
+		json.Unmarshal(b, &blocks)
+	}
+	return blocks
+}
+
+func (s *Storage) writePlan(blocks []tracker.PlannedBlock) error {
+	b, err := json.MarshalIndent(blocks, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.planPath(), b, 0644)
+}
@@ -5,6 +5,8 @@ import (
 	"katana/tracker"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 	"encoding/json"
 
@@ -34,6 +36,13 @@ func NewStorage() (*Storage, error) {
 			category TEXT,
 			tags TEXT
 		)`)
+		if err == nil {
+			migrateSessions(db)
+			err = createTables(db, billingSchema)
+		}
+		if err == nil {
+			err = createTables(db, planSchema)
+		}
 		if err == nil {
 			return &Storage{db: db, useSQLite: true}, nil
 		}
@@ -45,112 +54,202 @@ func NewStorage() (*Storage, error) {
 
 // SaveSession saves a session to the database or JSON
 func (s *Storage) SaveSession(sess *tracker.Session) error {
+	return s.SaveSessions([]*tracker.Session{sess})
+}
+
+// SaveSessions saves several sessions at once; either all of them are stored or none
+func (s *Storage) SaveSessions(sessions []*tracker.Session) error {
 	if s.useSQLite {
-		tagsJSON, _ := json.Marshal(sess.Tags)
-		_, err := s.db.Exec(`INSERT INTO sessions (start_time, end_time, duration, activity, category, tags) VALUES (?, ?, ?, ?, ?, ?)`,
-			sess.StartTime.Format(time.RFC3339),
-			sess.EndTime.Format(time.RFC3339),
-			sess.Duration.Milliseconds(),
-			sess.Activity,
-			sess.Category,
-			string(tagsJSON),
-		)
-		return err
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		for _, sess := range sessions {
+			if err := insertSession(tx, sess); err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+		return tx.Commit()
 	}
 	// Fallback: append to JSON file
-	var sessions []*tracker.Session
-	b, err := os.ReadFile(s.jsonPath)
-	if err == nil {
-		json.Unmarshal(b, &sessions)
+	all := s.readJSON()
+	for _, sess := range sessions {
+		sess.ID = nextJSONID(all)
+		all = append(all, sess)
 	}
-	sessions = append(sessions, sess)
-	data, err := json.MarshalIndent(sessions, "", "  ")
-	if err != nil {
-		return err
+	return s.writeJSON(all)
+}
+
+// ImportSessions saves the sessions that are not stored yet, skipping any
+// with the same start, end and activity as an existing session, so a file
+// can be imported more than once. It returns how many were added.
+func (s *Storage) ImportSessions(sessions []*tracker.Session) (int, error) {
+	var added []*tracker.Session
+	for _, sess := range sessions {
+		existing, err := s.LoadSessionsInRange(sess.StartTime, sess.EndTime)
+		if err != nil {
+			return 0, err
+		}
+		duplicate := false
+		for _, e := range existing {
+			if e.StartTime.Equal(sess.StartTime) && e.EndTime.Equal(sess.EndTime) && e.Activity == sess.Activity {
+				duplicate = true
+				break
+			}
+		}
+		for _, e := range added {
+			if e.StartTime.Equal(sess.StartTime) && e.EndTime.Equal(sess.EndTime) && e.Activity == sess.Activity {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			sess.ID = 0
+			added = append(added, sess)
+		}
 	}
-	return os.WriteFile(s.jsonPath, data, 0644)
+	if len(added) == 0 {
+		return 0, nil
+	}
+	return len(added), s.SaveSessions(added)
 }
 
-// LoadSessionsForDay loads all sessions for a given day (used for daily/weekly/monthly viewers)
-func (s *Storage) LoadSessionsForDay(day time.Time) ([]*tracker.Session, error) {
-	var sessions []*tracker.Session
+// UpdateSession overwrites the stored fields of an existing session
+func (s *Storage) UpdateSession(sess *tracker.Session) error {
+	return s.ReplaceSessions(nil, nil, []*tracker.Session{sess})
+}
+
+// ReplaceSessions atomically deletes the removed sessions, inserts the added
+// ones and rewrites the updated ones (used for split, merge and edits)
+func (s *Storage) ReplaceSessions(removed, added, updated []*tracker.Session) error {
 	if s.useSQLite {
-		start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
-		end := start.Add(24 * time.Hour)
-		rows, err := s.db.Query(`SELECT id, start_time, end_time, duration, activity, category, tags FROM sessions WHERE start_time >= ? AND start_time < ?`, start.Format(time.RFC3339), end.Format(time.RFC3339))
+		tx, err := s.db.Begin()
 		if err != nil {
-			return nil, err
+			return err
 		}
-		defer rows.Close()
-		for rows.Next() {
-			var sess tracker.Session
-			var startStr, endStr, tagsStr string
-			var duration int64
-			if err := rows.Scan(&sess.ID, &startStr, &endStr, &duration, &sess.Activity, &sess.Category, &tagsStr); err != nil {
-				continue
+		for _, sess := range removed {
+			if _, err := tx.Exec(`DELETE FROM sessions WHERE id = ?`, sess.ID); err != nil {
+				tx.Rollback()
+				return err
 			}
-			sess.StartTime, _ = time.Parse(time.RFC3339, startStr)
-			sess.EndTime, _ = time.Parse(time.RFC3339, endStr)
-			sess.Duration = time.Duration(duration) * time.Millisecond
-			json.Unmarshal([]byte(tagsStr), &sess.Tags)
-			sessions = append(sessions, &sess)
 		}
-		return sessions, nil
+		for _, sess := range updated {
+			if err := updateSession(tx, sess); err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+		for _, sess := range added {
+			if err := insertSession(tx, sess); err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+		return tx.Commit()
 	}
-	// Fallback: load from JSON file
-	b, err := os.ReadFile(s.jsonPath)
-	if err != nil {
-		return nil, nil
+	// Fallback: rewrite the whole JSON file once
+	drop := make(map[int64]bool)
+	for _, sess := range removed {
+		drop[sess.ID] = true
 	}
-	json.Unmarshal(b, &sessions)
-	var filtered []*tracker.Session
-	for _, sess := range sessions {
-		if sameDay(sess.StartTime, day) {
-			filtered = append(filtered, sess)
+	changed := make(map[int64]*tracker.Session)
+	for _, sess := range updated {
+		changed[sess.ID] = sess
+	}
+	var all []*tracker.Session
+	for _, sess := range s.readJSON() {
+		if drop[sess.ID] {
+			continue
+		}
+		if c, ok := changed[sess.ID]; ok {
+			sess = c
 		}
+		all = append(all, sess)
 	}
-	return filtered, nil
+	for _, sess := range added {
+		sess.ID = nextJSONID(all)
+		all = append(all, sess)
+	}
+	return s.writeJSON(all)
 }
 
-// LoadSessionsForMonth loads all sessions for a given month
-func (s *Storage) LoadSessionsForMonth(year int, month time.Month) ([]*tracker.Session, error) {
-	var sessions []*tracker.Session
+// LoadSessionsInRange loads all sessions overlapping [start, end), ordered by start time
+func (s *Storage) LoadSessionsInRange(start, end time.Time) ([]*tracker.Session, error) {
 	if s.useSQLite {
-		start := time.Date(year, month, 1, 0, 0, 0, 0, time.Local)
-		end := start.AddDate(0, 1, 0) // First day of next month
-		rows, err := s.db.Query(`SELECT id, start_time, end_time, duration, activity, category, tags FROM sessions WHERE start_time >= ? AND start_time < ? ORDER BY start_time ASC`, start.Format(time.RFC3339), end.Format(time.RFC3339))
+		rows, err := s.db.Query(`SELECT `+sessionColumns+` FROM sessions WHERE start_time < ? AND end_time > ? ORDER BY start_time ASC`, end.Format(time.RFC3339), start.Format(time.RFC3339))
 		if err != nil {
 			return nil, err
 		}
 		defer rows.Close()
-		for rows.Next() {
-			var sess tracker.Session
-			var startStr, endStr, tagsStr string
-			var duration int64
-			if err := rows.Scan(&sess.ID, &startStr, &endStr, &duration, &sess.Activity, &sess.Category, &tagsStr); err != nil {
-				continue
+		return scanSessions(rows), nil
+	}
+	var filtered []*tracker.Session
+	for _, sess := range s.readJSON() {
+		if sess.StartTime.Before(end) && sess.EndTime.After(start) {
+			filtered = append(filtered, sess)
+		}
+	}
+	sort.Slice(filtered, func(i, j int) bool {
+		return filtered[i].StartTime.Before(filtered[j].StartTime)
+	})
+	return filtered, nil
+}
+
+// EachSessionInRange calls fn for every session overlapping [start, end) in
+// order of start time, one row at a time so large histories are never held
+// in memory at once. It stops at the first error fn returns.
+func (s *Storage) EachSessionInRange(start, end time.Time, fn func(*tracker.Session) error) error {
+	if !s.useSQLite {
+		sessions, err := s.LoadSessionsInRange(start, end)
+		if err != nil {
+			return err
+		}
+		for _, sess := range sessions {
+			if err := fn(sess); err != nil {
+				return err
 			}
-			sess.StartTime, _ = time.Parse(time.RFC3339, startStr)
-			sess.EndTime, _ = time.Parse(time.RFC3339, endStr)
-			sess.Duration = time.Duration(duration) * time.Millisecond
-			json.Unmarshal([]byte(tagsStr), &sess.Tags)
-			sessions = append(sessions, &sess)
 		}
-		return sessions, nil
+		return nil
 	}
-	// Fallback: load from JSON file
-	b, err := os.ReadFile(s.jsonPath)
+	rows, err := s.db.Query(`SELECT `+sessionColumns+` FROM sessions WHERE start_time < ? AND end_time > ? ORDER BY start_time ASC`, end.Format(time.RFC3339), start.Format(time.RFC3339))
 	if err != nil {
-		return sessions, nil // Return empty slice instead of nil
+		return err
 	}
-	json.Unmarshal(b, &sessions)
-	var filtered []*tracker.Session
-	for _, sess := range sessions {
-		if sess.StartTime.Year() == year && sess.StartTime.Month() == month {
-			filtered = append(filtered, sess)
+	defer rows.Close()
+	for rows.Next() {
+		sess, err := scanSession(rows)
+		if err != nil {
+			continue
+		}
+		if err := fn(sess); err != nil {
+			return err
 		}
 	}
-	return filtered, nil
+	return rows.Err()
+}
+
+// LoadSessionsForDay loads the sessions for a given day (used for daily/weekly/monthly viewers).
+// Sessions crossing midnight are clipped so only the part on this day is returned.
+func (s *Storage) LoadSessionsForDay(day time.Time) ([]*tracker.Session, error) {
+	start := tracker.DayStart(day)
+	end := start.AddDate(0, 0, 1)
+	sessions, err := s.LoadSessionsInRange(start, end)
+	if err != nil {
+		return nil, err
+	}
+	return tracker.ClipSessions(sessions, start, end), nil
+}
+
+// LoadSessionsForMonth loads all sessions for a given month, clipped to the month
+func (s *Storage) LoadSessionsForMonth(year int, month time.Month) ([]*tracker.Session, error) {
+	start := time.Date(year, month, 1, 0, 0, 0, 0, time.Local)
+	end := start.AddDate(0, 1, 0) // First day of next month
+	sessions, err := s.LoadSessionsInRange(start, end)
+	if err != nil {
+		return nil, err
+	}
+	return tracker.ClipSessions(sessions, start, end), nil
 }
 
 // Close properly closes database connections
@@ -165,25 +264,12 @@ func (s *Storage) Close() error {
 func (s *Storage) GetAllSessions() ([]*tracker.Session, error) {
 	var sessions []*tracker.Session
 	if s.useSQLite {
-		rows, err := s.db.Query(`SELECT id, start_time, end_time, duration, activity, category, tags FROM sessions ORDER BY start_time DESC`)
+		rows, err := s.db.Query(`SELECT `+sessionColumns+` FROM sessions ORDER BY start_time DESC`)
 		if err != nil {
 			return nil, err
 		}
 		defer rows.Close()
-		for rows.Next() {
-			var sess tracker.Session
-			var startStr, endStr, tagsStr string
-			var duration int64
-			if err := rows.Scan(&sess.ID, &startStr, &endStr, &duration, &sess.Activity, &sess.Category, &tagsStr); err != nil {
-				continue
-			}
-			sess.StartTime, _ = time.Parse(time.RFC3339, startStr)
-			sess.EndTime, _ = time.Parse(time.RFC3339, endStr)
-			sess.Duration = time.Duration(duration) * time.Millisecond
-			json.Unmarshal([]byte(tagsStr), &sess.Tags)
-			sessions = append(sessions, &sess)
-		}
-		return sessions, nil
+		return scanSessions(rows), nil
 	}
 	// Fallback: load from JSON file
 	b, err := os.ReadFile(s.jsonPath)
@@ -194,8 +280,168 @@ func (s *Storage) GetAllSessions() ([]*tracker.Session, error) {
 	return sessions, nil
 }
 
-func sameDay(t1, t2 time.Time) bool {
-	y1, m1, d1 := t1.Date()
-	y2, m2, d2 := t2.Date()
-	return y1 == y2 && m1 == m2 && d1 == d2
+// SearchSessions returns sessions whose activity, notes, category, project or
+// tags contain every word of query, newest first
+func (s *Storage) SearchSessions(query string) ([]*tracker.Session, error) {
+	words := strings.Fields(query)
+	if len(words) == 0 {
+		return nil, nil
+	}
+	if s.useSQLite {
+		var clauses []string
+		var args []interface{}
+		for _, w := range words {
+			pattern := "%" + likeEscaper.Replace(w) + "%"
+			clauses = append(clauses, `(activity LIKE ? ESCAPE '\' OR notes LIKE ? ESCAPE '\' OR category LIKE ? ESCAPE '\' OR project LIKE ? ESCAPE '\' OR tags LIKE ? ESCAPE '\')`)
+			args = append(args, pattern, pattern, pattern, pattern, pattern)
+		}
+		rows, err := s.db.Query(`SELECT `+sessionColumns+` FROM sessions WHERE `+strings.Join(clauses, " AND ")+` ORDER BY start_time DESC`, args...)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+		var matches []*tracker.Session
+		// LIKE also matches JSON punctuation in tags, so confirm each hit
+		for _, sess := range scanSessions(rows) {
+			if sess.MatchesQuery(query) {
+				matches = append(matches, sess)
+			}
+		}
+		return matches, nil
+	}
+	var matches []*tracker.Session
+	for _, sess := range s.readJSON() {
+		if sess.MatchesQuery(query) {
+			matches = append(matches, sess)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].StartTime.After(matches[j].StartTime)
+	})
+	return matches, nil
+}
+
+// likeEscaper escapes the LIKE wildcards in a search word
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+const sessionColumns = `id, start_time, end_time, duration, activity, category, tags, pomodoro, project, billable, notes`
+
+// sessionMigrations adds columns introduced after the original schema.
+// ALTER TABLE fails harmlessly when a column already exists.
+var sessionMigrations = []string{
+	`ALTER TABLE sessions ADD COLUMN pomodoro INTEGER NOT NULL DEFAULT 0`,
+	`ALTER TABLE sessions ADD COLUMN project TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE sessions ADD COLUMN billable INTEGER NOT NULL DEFAULT 0`,
+	`ALTER TABLE sessions ADD COLUMN notes TEXT NOT NULL DEFAULT ''`,
+}
+
+// migrateSessions brings an existing sessions table up to date
+func migrateSessions(db *sql.DB) {
+	for _, m := range sessionMigrations {
+		db.Exec(m)
+	}
+}
+
+// execer is satisfied by both *sql.DB and *sql.Tx
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// insertSession inserts sess and stores the new row ID back into it
+func insertSession(db execer, sess *tracker.Session) error {
+	tagsJSON, _ := json.Marshal(sess.Tags)
+	res, err := db.Exec(`INSERT INTO sessions (start_time, end_time, duration, activity, category, tags, pomodoro, project, billable, notes) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		sess.StartTime.Format(time.RFC3339),
+		sess.EndTime.Format(time.RFC3339),
+		sess.Duration.Milliseconds(),
+		sess.Activity,
+		sess.Category,
+		string(tagsJSON),
+		sess.Pomodoro,
+		sess.Project,
+		sess.Billable,
+		sess.Notes,
+	)
+	if err != nil {
+		return err
+	}
+	if id, err := res.LastInsertId(); err == nil {
+		sess.ID = id
+	}
+	return nil
+}
+
+// updateSession rewrites every column of the row with sess.ID
+func updateSession(db execer, sess *tracker.Session) error {
+	tagsJSON, _ := json.Marshal(sess.Tags)
+	_, err := db.Exec(`UPDATE sessions SET start_time = ?, end_time = ?, duration = ?, activity = ?, category = ?, tags = ?, pomodoro = ?, project = ?, billable = ?, notes = ? WHERE id = ?`,
+		sess.StartTime.Format(time.RFC3339),
+		sess.EndTime.Format(time.RFC3339),
+		sess.Duration.Milliseconds(),
+		sess.Activity,
+		sess.Category,
+		string(tagsJSON),
+		sess.Pomodoro,
+		sess.Project,
+		sess.Billable,
+		sess.Notes,
+		sess.ID,
+	)
+	return err
+}
+
+// scanSessions reads all rows selected with sessionColumns, skipping malformed ones
+func scanSessions(rows *sql.Rows) []*tracker.Session {
+	var sessions []*tracker.Session
+	for rows.Next() {
+		if sess, err := scanSession(rows); err == nil {
+			sessions = append(sessions, sess)
+		}
+	}
+	return sessions
+}
+
+// scanSession reads the current row selected with sessionColumns
+func scanSession(rows *sql.Rows) (*tracker.Session, error) {
+	var sess tracker.Session
+	var startStr, endStr, tagsStr string
+	var duration int64
+	if err := rows.Scan(&sess.ID, &startStr, &endStr, &duration, &sess.Activity, &sess.Category, &tagsStr, &sess.Pomodoro, &sess.Project, &sess.Billable, &sess.Notes); err != nil {
+		return nil, err
+	}
+	sess.StartTime, _ = time.Parse(time.RFC3339, startStr)
+	sess.EndTime, _ = time.Parse(time.RFC3339, endStr)
+	sess.Duration = time.Duration(duration) * time.Millisecond
+	json.Unmarshal([]byte(tagsStr), &sess.Tags)
+	return &sess, nil
+}
+
+// readJSON loads every session from the JSON fallback file
+func (s *Storage) readJSON() []*tracker.Session {
+	var sessions []*tracker.Session
+	b, err := os.ReadFile(s.jsonPath)
+	if err == nil {
+		json.Unmarshal(b, &sessions)
+	}
+	return sessions
+}
+
+// writeJSON replaces the JSON fallback file with the given sessions
+func (s *Storage) writeJSON(sessions []*tracker.Session) error {
+	data, err := json.MarshalIndent(sessions, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.jsonPath, data, 0644)
+}
+
+// nextJSONID returns an ID one greater than any used in the JSON fallback
+func nextJSONID(sessions []*tracker.Session) int64 {
+	var max int64
+	for _, sess := range sessions {
+		if sess.ID > max {
+			max = sess.ID
+		}
+	}
+	return max + 1
 }
@@ -0,0 +1,162 @@
+package tracker
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Client is a customer that billable work is invoiced to
+type Client struct {
+	ID         int64
+	Name       string
+	Currency   string  // ISO code such as "EUR" or "USD"
+	HourlyRate float64 // Default rate for the client's projects
+	Address    string  // Printed on invoices, may span several lines
+}
+
+// Project groups sessions for a client. Sessions refer to projects by name.
+type Project struct {
+	ID         int64
+	Name       string
+	ClientID   int64
+	HourlyRate float64 // Overrides the client's rate when greater than zero
+}
+
+// Rate returns the hourly rate that applies to the project
+func (p Project) Rate(c Client) float64 {
+	if p.HourlyRate > 0 {
+		return p.HourlyRate
+	}
+	return c.HourlyRate
+}
+
+// RoundingMode selects the direction durations are rounded in
+type RoundingMode string
+
+const (
+	RoundNone    RoundingMode = "none"
+	RoundUp      RoundingMode = "up"
+	RoundNearest RoundingMode = "nearest"
+	RoundDown    RoundingMode = "down"
+)
+
+// RoundingScope selects whether each session or each day's total is rounded
+type RoundingScope string
+
+const (
+	PerSession RoundingScope = "session"
+	PerDay     RoundingScope = "day"
+)
+
+// RoundingRule describes how tracked time is rounded before billing,
+// e.g. up to the nearest 6 minutes per session
+type RoundingRule struct {
+	IncrementMinutes int           `json:"increment_minutes"`
+	Mode             RoundingMode  `json:"mode"`
+	Scope            RoundingScope `json:"scope"`
+}
+
+// Round applies the rule to a single duration
+func (r RoundingRule) Round(d time.Duration) time.Duration {
+	inc := time.Duration(r.IncrementMinutes) * time.Minute
+	if inc <= 0 || r.Mode == RoundNone || r.Mode == "" {
+		return d
+	}
+	switch r.Mode {
+	case RoundUp:
+		if rem := d % inc; rem != 0 {
+			return d - rem + inc
+		}
+		return d
+	case RoundDown:
+		return d - d%inc
+	default:
+		return d.Round(inc)
+	}
+}
+
+// BillLine is one invoice line: a session, or a day's work on a project
+type BillLine struct {
+	Date        time.Time
+	Project     string
+	Description string
+	Tracked     time.Duration // Time as recorded
+	Billed      time.Duration // Time after rounding
+	Rate        float64
+}
+
+// Amount returns the line total
+func (l BillLine) Amount() float64 {
+	return l.Billed.Hours() * l.Rate
+}
+
+// BillLines builds invoice lines for the client's billable sessions, applying
+// the rounding rule per session or per day and project
+func BillLines(sessions []*Session, client Client, projects []Project, rule RoundingRule) []BillLine {
+	rates := make(map[string]float64)
+	for _, p := range projects {
+		if p.ClientID == client.ID {
+			rates[p.Name] = p.Rate(client)
+		}
+	}
+
+	var billable []*Session
+	for _, s := range sessions {
+		if _, ok := rates[s.Project]; ok && s.Billable {
+			billable = append(billable, s)
+		}
+	}
+	sort.Slice(billable, func(i, j int) bool {
+		return billable[i].StartTime.Before(billable[j].StartTime)
+	})
+
+	var lines []BillLine
+	if rule.Scope == PerDay {
+		index := make(map[string]int)
+		for _, whole := range billable {
+			// Work past midnight is billed on the day it was done
+			for _, s := range SplitByDay(whole) {
+				day := DayStart(s.StartTime)
+				key := day.Format("2006-01-02") + "|" + s.Project
+				i, ok := index[key]
+				if !ok {
+					i = len(lines)
+					index[key] = i
+					lines = append(lines, BillLine{Date: day, Project: s.Project, Rate: rates[s.Project]})
+				}
+				lines[i].Tracked += s.Duration
+				lines[i].Description = joinDescription(lines[i].Description, s.Activity)
+			}
+		}
+		for i := range lines {
+			lines[i].Billed = rule.Round(lines[i].Tracked)
+		}
+		return lines
+	}
+
+	for _, s := range billable {
+		lines = append(lines, BillLine{
+			Date:        s.StartTime,
+			Project:     s.Project,
+			Description: s.Activity,
+			Tracked:     s.Duration,
+			Billed:      rule.Round(s.Duration),
+			Rate:        rates[s.Project],
+		})
+	}
+	return lines
+}
+
+// joinDescription appends an activity to a line description unless already listed
+func joinDescription(desc, activity string) string {
+	if desc == "" {
+		return activity
+	}
+	for _, part := range strings.Split(desc, ", ") {
+		if part == activity {
+			return desc
+		}
+	}
+	return desc + ", " + activity
+}
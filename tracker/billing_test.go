@@ -0,0 +1,103 @@
+package tracker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRoundingRuleRound(t *testing.T) {
+	minutes := func(m int) time.Duration { return time.Duration(m) * time.Minute }
+	tests := []struct {
+		mode RoundingMode
+		inc  int
+		in   time.Duration
+		want time.Duration
+	}{
+		{RoundUp, 6, minutes(31), minutes(36)},
+		{RoundUp, 6, minutes(30), minutes(30)},
+		{RoundUp, 6, time.Second, minutes(6)},
+		{RoundDown, 15, minutes(44), minutes(30)},
+		{RoundNearest, 15, minutes(37), minutes(30)},
+		{RoundNearest, 15, minutes(38), minutes(45)},
+		{RoundNone, 15, minutes(38), minutes(38)},
+		{"", 15, minutes(38), minutes(38)},
+		{RoundUp, 0, minutes(31), minutes(31)},
+	}
+	for _, tt := range tests {
+		rule := RoundingRule{IncrementMinutes: tt.inc, Mode: tt.mode}
+		if got := rule.Round(tt.in); got != tt.want {
+			t.Errorf("%q by %dm of %v = %v, want %v", tt.mode, tt.inc, tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestBillLines(t *testing.T) {
+	client := Client{ID: 1, Name: "Acme", Currency: "EUR", HourlyRate: 100}
+	projects := []Project{
+		{Name: "site", ClientID: 1},
+		{Name: "audit", ClientID: 1, HourlyRate: 150},
+		{Name: "other", ClientID: 2, HourlyRate: 80},
+	}
+	session := func(activity, project string, start, end time.Time, billable bool) *Session {
+		s := NewSessionAt(activity, start, end)
+		s.Project, s.Billable = project, billable
+		return s
+	}
+	late := time.Date(2026, 3, 2, 23, 0, 0, 0, time.Local)
+	sessions := []*Session{
+		session("design", "site", at(10, 0), at(10, 31), true),
+		session("build", "site", at(9, 0), at(9, 20), true),
+		session("checks", "audit", at(11, 0), at(11, 50), true),
+		session("lunch", "site", at(12, 0), at(13, 0), false),
+		session("elsewhere", "other", at(14, 0), at(15, 0), true),
+		session("deploy", "site", late, late.Add(2*time.Hour), true),
+	}
+	type line struct {
+		date    string
+		project string
+		desc    string
+		tracked time.Duration
+		billed  time.Duration
+		rate    float64
+	}
+	minutes := func(m int) time.Duration { return time.Duration(m) * time.Minute }
+	tests := []struct {
+		name  string
+		scope RoundingScope
+		want  []line
+	}{
+		{"per session", PerSession, []line{
+			{"2026-03-02", "site", "build", minutes(20), minutes(24), 100},
+			{"2026-03-02", "site", "design", minutes(31), minutes(36), 100},
+			{"2026-03-02", "audit", "checks", minutes(50), minutes(54), 150},
+			{"2026-03-02", "site", "deploy", minutes(120), minutes(120), 100},
+		}},
+		{"per day splits at midnight", PerDay, []line{
+			{"2026-03-02", "site", "build, design, deploy", minutes(111), minutes(114), 100},
+			{"2026-03-02", "audit", "checks", minutes(50), minutes(54), 150},
+			{"2026-03-03", "site", "deploy", minutes(60), minutes(60), 100},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := RoundingRule{IncrementMinutes: 6, Mode: RoundUp, Scope: tt.scope}
+			lines := BillLines(sessions, client, projects, rule)
+			if len(lines) != len(tt.want) {
+				t.Fatalf("got %d lines, want %d: %+v", len(lines), len(tt.want), lines)
+			}
+			for i, l := range lines {
+				got := line{l.Date.Format("2006-01-02"), l.Project, l.Description, l.Tracked, l.Billed, l.Rate}
+				if got != tt.want[i] {
+					t.Errorf("line %d = %+v, want %+v", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestBillLineAmount(t *testing.T) {
+	l := BillLine{Billed: 90 * time.Minute, Rate: 80}
+	if got := l.Amount(); got != 120 {
+		t.Errorf("Amount = %v, want 120", got)
+	}
+}
@@ -0,0 +1,65 @@
+package tracker
+
+import (
+	"sort"
+	"time"
+)
+
+// OverlapPolicy decides how time covered by several sessions at once is totalled
+type OverlapPolicy string
+
+const (
+	// CountPerSession adds up every session's full duration
+	CountPerSession OverlapPolicy = "per_session"
+	// CountOnce counts wall-clock time covered by concurrent sessions only once
+	CountOnce OverlapPolicy = "once"
+)
+
+// TotalTracked sums the tracked time of sessions under the given policy.
+// With CountOnce each stretch of time counts at most once; a session whose
+// duration is shorter than its span (because of pauses or merges) is assumed
+// to be spread evenly over that span.
+func TotalTracked(sessions []*Session, policy OverlapPolicy) time.Duration {
+	if policy != CountOnce {
+		var total time.Duration
+		for _, s := range sessions {
+			total += s.Duration
+		}
+		return total
+	}
+
+	var spans []*Session
+	var bounds []time.Time
+	for _, s := range sessions {
+		if !s.EndTime.After(s.StartTime) || s.Duration <= 0 {
+			continue
+		}
+		spans = append(spans, s)
+		bounds = append(bounds, s.StartTime, s.EndTime)
+	}
+	sort.Slice(bounds, func(i, j int) bool { return bounds[i].Before(bounds[j]) })
+
+	var total float64
+	for i := 1; i < len(bounds); i++ {
+		from, to := bounds[i-1], bounds[i]
+		if !to.After(from) {
+			continue
+		}
+		// The busiest session covering this stretch decides how much of it was tracked
+		density := 0.0
+		for _, s := range spans {
+			if s.StartTime.After(from) || s.EndTime.Before(to) {
+				continue
+			}
+			d := float64(s.Duration) / float64(s.EndTime.Sub(s.StartTime))
+			if d > 1 {
+				d = 1
+			}
+			if d > density {
+				density = d
+			}
+		}
+		total += density * float64(to.Sub(from))
+	}
+	return time.Duration(total)
+}
@@ -0,0 +1,87 @@
+package tracker
+
+import "time"
+
+// DayStart returns midnight at the start of t's calendar day
+func DayStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// Clip returns the part of a finished session within [start, end), or nil if
+// it lies outside that range. A session entirely inside the range is returned
+// as is; otherwise a copy is made that keeps the session's ID, with the share
+// of the tracked time that falls in the range, assuming pauses are spread
+// evenly over the span.
+func (s *Session) Clip(start, end time.Time) *Session {
+	if !s.StartTime.Before(end) || !s.EndTime.After(start) {
+		return nil
+	}
+	if !s.StartTime.Before(start) && !s.EndTime.After(end) {
+		return s
+	}
+	c := *s
+	c.Tags = append([]string(nil), s.Tags...)
+	if c.StartTime.Before(start) {
+		c.StartTime = start
+		// A pomodoro is counted on the day it began
+		c.Pomodoro = false
+	}
+	if c.EndTime.After(end) {
+		c.EndTime = end
+	}
+	span, part := s.EndTime.Sub(s.StartTime), c.EndTime.Sub(c.StartTime)
+	if s.Duration == span {
+		// Without pauses the parts add up exactly
+		c.Duration = part
+	} else {
+		c.Duration = time.Duration(float64(s.Duration) * float64(part) / float64(span))
+	}
+	return &c
+}
+
+// SessionsForDay returns the parts of sessions that fall on day's calendar day
+func SessionsForDay(sessions []*Session, day time.Time) []*Session {
+	start := DayStart(day)
+	return ClipSessions(sessions, start, start.AddDate(0, 0, 1))
+}
+
+// ClipSessions clips every session to [start, end), dropping those outside it
+func ClipSessions(sessions []*Session, start, end time.Time) []*Session {
+	var clipped []*Session
+	for _, s := range sessions {
+		if c := s.Clip(start, end); c != nil {
+			clipped = append(clipped, c)
+		}
+	}
+	return clipped
+}
+
+// SplitByDay cuts a finished session into one part per calendar day it touches
+func SplitByDay(s *Session) []*Session {
+	var parts []*Session
+	for day := DayStart(s.StartTime); day.Before(s.EndTime); day = day.AddDate(0, 0, 1) {
+		if c := s.Clip(day, day.AddDate(0, 0, 1)); c != nil {
+			parts = append(parts, c)
+		}
+	}
+	return parts
+}
+
+// carryOver ends a running session at the given time and restarts it from
+// there. It returns the finished part; s keeps running as a new, unsaved
+// session.
+func (s *Session) carryOver(at time.Time) *Session {
+	done := *s
+	done.Tags = append([]string(nil), s.Tags...)
+	done.StopAt(at)
+
+	s.ID = 0
+	s.StartTime = at
+	s.PausedDuration = 0
+	s.Pomodoro = false
+	if s.IsPaused() && s.pausedAt.Before(at) {
+		// Still away: the pause carries over into the new day
+		s.pausedAt = at
+	}
+	return &done
+}
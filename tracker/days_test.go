@@ -0,0 +1,88 @@
+package tracker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSplitByDay(t *testing.T) {
+	day := func(d, hour int) time.Time { return time.Date(2026, 3, d, hour, 0, 0, 0, time.Local) }
+	tests := []struct {
+		name      string
+		start     time.Time
+		end       time.Time
+		paused    time.Duration
+		want      []string      // Spans of the parts
+		wantTotal time.Duration // Sum of the parts' durations
+	}{
+		{"within a day", day(2, 9), day(2, 17), 0, []string{"09:00-17:00"}, 8 * time.Hour},
+		{"across midnight", day(2, 22), day(3, 2), 0, []string{"22:00-00:00", "00:00-02:00"}, 4 * time.Hour},
+		{"ends at midnight", day(2, 22), day(3, 0), 0, []string{"22:00-00:00"}, 2 * time.Hour},
+		{"starts at midnight", day(3, 0), day(3, 2), 0, []string{"00:00-02:00"}, 2 * time.Hour},
+		{"over two midnights", day(2, 23), day(4, 1), 0, []string{"23:00-00:00", "00:00-00:00", "00:00-01:00"}, 26 * time.Hour},
+		{"pauses spread over the days", day(2, 22), day(3, 2), time.Hour, []string{"22:00-00:00", "00:00-02:00"}, 3 * time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSessionAt("write", tt.start, tt.end)
+			s.Duration -= tt.paused
+			parts := SplitByDay(s)
+			if len(parts) != len(tt.want) {
+				t.Fatalf("got %d parts, want %v", len(parts), tt.want)
+			}
+			var total time.Duration
+			for i, p := range parts {
+				if span(p) != tt.want[i] {
+					t.Errorf("part %d = %s, want %s", i, span(p), tt.want[i])
+				}
+				if !DayStart(p.StartTime).Equal(DayStart(p.EndTime.Add(-time.Nanosecond))) {
+					t.Errorf("part %d spans more than one day", i)
+				}
+				total += p.Duration
+			}
+			if total != tt.wantTotal {
+				t.Errorf("total = %v, want %v", total, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestClip(t *testing.T) {
+	midnight := time.Date(2026, 3, 3, 0, 0, 0, 0, time.Local)
+	s := NewSessionAt("write", midnight.Add(-time.Hour), midnight.Add(3*time.Hour))
+	s.ID = 4
+	s.Pomodoro = true
+	tests := []struct {
+		name         string
+		start, end   time.Time
+		want         string // Empty when nothing is left
+		wantDur      time.Duration
+		wantPomodoro bool
+	}{
+		{"whole range", midnight.AddDate(0, 0, -1), midnight.AddDate(0, 0, 1), "23:00-03:00", 4 * time.Hour, true},
+		{"day before", midnight.AddDate(0, 0, -1), midnight, "23:00-00:00", time.Hour, true},
+		{"day after", midnight, midnight.AddDate(0, 0, 1), "00:00-03:00", 3 * time.Hour, false},
+		{"outside", midnight.Add(3 * time.Hour), midnight.Add(4 * time.Hour), "", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := s.Clip(tt.start, tt.end)
+			if tt.want == "" {
+				if c != nil {
+					t.Fatalf("Clip = %s, want nil", span(c))
+				}
+				return
+			}
+			if c == nil {
+				t.Fatalf("Clip = nil, want %s", tt.want)
+			}
+			if span(c) != tt.want || c.Duration != tt.wantDur || c.Pomodoro != tt.wantPomodoro || c.ID != s.ID {
+				t.Errorf("Clip = %s (%v, pomodoro %v, ID %d), want %s (%v, pomodoro %v, ID %d)",
+					span(c), c.Duration, c.Pomodoro, c.ID, tt.want, tt.wantDur, tt.wantPomodoro, s.ID)
+			}
+		})
+	}
+	if span(s) != "23:00-03:00" || s.Duration != 4*time.Hour {
+		t.Errorf("Clip changed the original session: %s (%v)", span(s), s.Duration)
+	}
+}
@@ -0,0 +1,120 @@
+package tracker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Split cuts a completed session at the given time into two sessions.
+// Both halves get their own copy of the tags so they can be edited independently.
+func Split(s *Session, at time.Time) (*Session, *Session, error) {
+	if s.EndTime.IsZero() {
+		return nil, nil, fmt.Errorf("cannot split a running session")
+	}
+	if !at.After(s.StartTime) || !at.Before(s.EndTime) {
+		return nil, nil, fmt.Errorf("split time must be between %s and %s",
+			s.StartTime.Format("15:04"), s.EndTime.Format("15:04"))
+	}
+
+	first := s.copyWithin(s.StartTime, at)
+	second := s.copyWithin(at, s.EndTime)
+
+	// Keep the tracked total unchanged when the session was shorter than its span
+	if first.Duration > s.Duration {
+		first.Duration = s.Duration
+	}
+	second.Duration = s.Duration - first.Duration
+	// A pomodoro is counted once, on the half where it began
+	second.Pomodoro = false
+	return first, second, nil
+}
+
+// Merge combines sessions of the same activity into a single session spanning
+// all of them. The merged duration is the sum of the parts, so gaps between
+// them are not counted as tracked time. Completed pomodoros are not merged,
+// since each one counts on its own.
+func Merge(sessions []*Session) (*Session, error) {
+	if len(sessions) < 2 {
+		return nil, fmt.Errorf("select at least two sessions to merge")
+	}
+	sorted := append([]*Session(nil), sessions...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].StartTime.Before(sorted[j].StartTime)
+	})
+
+	merged := sorted[0].copyWithin(sorted[0].StartTime, sorted[0].EndTime)
+	merged.Duration = 0
+	seenTags := make(map[string]bool)
+	merged.Tags = nil
+	merged.Notes = ""
+	for _, s := range sorted {
+		if s.EndTime.IsZero() {
+			return nil, fmt.Errorf("cannot merge a running session")
+		}
+		if s.Activity != merged.Activity {
+			return nil, fmt.Errorf("cannot merge %q with %q: activities differ", merged.Activity, s.Activity)
+		}
+		if s.Pomodoro {
+			return nil, fmt.Errorf("cannot merge the completed pomodoro at %s", s.StartTime.Format("15:04"))
+		}
+		if merged.Category == "" {
+			merged.Category = s.Category
+		}
+		for _, t := range s.Tags {
+			if !seenTags[t] {
+				seenTags[t] = true
+				merged.Tags = append(merged.Tags, t)
+			}
+		}
+		merged.Notes = joinNotes(merged.Notes, s.Notes)
+		if s.EndTime.After(merged.EndTime) {
+			merged.EndTime = s.EndTime
+		}
+		merged.Duration += s.Duration
+	}
+	return merged, nil
+}
+
+// joinNotes appends the notes of a merged part as a new paragraph, skipping
+// empty and repeated notes
+func joinNotes(notes, more string) string {
+	more = strings.TrimSpace(more)
+	if more == "" || strings.Contains(notes, more) {
+		return notes
+	}
+	if notes == "" {
+		return more
+	}
+	return notes + "\n\n" + more
+}
+
+// CheckAdjacent returns an error if any session in all, other than the selected
+// ones, starts between the first and last selected session
+func CheckAdjacent(selected, all []*Session) error {
+	if len(selected) == 0 {
+		return nil
+	}
+	first, last := selected[0].StartTime, selected[0].StartTime
+	chosen := make(map[*Session]bool)
+	for _, s := range selected {
+		chosen[s] = true
+		if s.StartTime.Before(first) {
+			first = s.StartTime
+		}
+		if s.StartTime.After(last) {
+			last = s.StartTime
+		}
+	}
+	for _, s := range all {
+		if chosen[s] {
+			continue
+		}
+		if s.StartTime.After(first) && s.StartTime.Before(last) {
+			return fmt.Errorf("sessions are not adjacent: %q at %s lies between them",
+				s.Activity, s.StartTime.Format("15:04"))
+		}
+	}
+	return nil
+}
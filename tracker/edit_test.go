@@ -0,0 +1,136 @@
+package tracker
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name       string
+		paused     time.Duration
+		at         time.Time
+		wantFirst  time.Duration
+		wantSecond time.Duration
+		wantErr    bool
+	}{
+		{"even", 0, at(9, 40), 40 * time.Minute, 20 * time.Minute, false},
+		{"paused time stays on the second half", 30 * time.Minute, at(9, 20), 20 * time.Minute, 10 * time.Minute, false},
+		{"pause longer than the first half", 50 * time.Minute, at(9, 20), 10 * time.Minute, 0, false},
+		{"at the start", 0, at(9, 0), 0, 0, true},
+		{"at the end", 0, at(10, 0), 0, 0, true},
+		{"outside", 0, at(11, 0), 0, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSessionAt("write", at(9, 0), at(10, 0))
+			s.Duration -= tt.paused
+			s.Tags = []string{"draft"}
+			s.Pomodoro = true
+			first, second, err := Split(s, tt.at)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !first.EndTime.Equal(tt.at) || !second.StartTime.Equal(tt.at) {
+				t.Errorf("halves meet at %v and %v, want %v", first.EndTime, second.StartTime, tt.at)
+			}
+			if first.Duration != tt.wantFirst || second.Duration != tt.wantSecond {
+				t.Errorf("durations = %v + %v, want %v + %v", first.Duration, second.Duration, tt.wantFirst, tt.wantSecond)
+			}
+			if !first.Pomodoro || second.Pomodoro {
+				t.Errorf("pomodoro = %v, %v; want it only on the first half", first.Pomodoro, second.Pomodoro)
+			}
+			first.Tags[0] = "edited"
+			if second.Tags[0] != "draft" || s.Tags[0] != "draft" {
+				t.Errorf("the halves share their tags")
+			}
+		})
+	}
+}
+
+func TestSplitRunning(t *testing.T) {
+	s := NewSession("write")
+	s.StartTime = at(9, 0)
+	if _, _, err := Split(s, at(9, 30)); err == nil {
+		t.Error("splitting a running session succeeded")
+	}
+}
+
+func TestMerge(t *testing.T) {
+	part := func(activity string, start, end time.Time, tags []string, notes string) *Session {
+		s := NewSessionAt(activity, start, end)
+		s.Tags = tags
+		s.Notes = notes
+		return s
+	}
+	tests := []struct {
+		name      string
+		sessions  []*Session
+		wantSpan  string
+		wantDur   time.Duration
+		wantTags  []string
+		wantNotes string
+		wantErr   bool
+	}{
+		{
+			name: "gap is not counted",
+			sessions: []*Session{
+				part("write", at(11, 0), at(11, 30), []string{"b"}, "second"),
+				part("write", at(9, 0), at(10, 0), []string{"a", "b"}, "first"),
+			},
+			wantSpan: "09:00-11:30", wantDur: 90 * time.Minute,
+			wantTags: []string{"a", "b"}, wantNotes: "first\n\nsecond",
+		},
+		{
+			name: "repeated notes kept once",
+			sessions: []*Session{
+				part("write", at(9, 0), at(10, 0), nil, "same"),
+				part("write", at(10, 0), at(11, 0), nil, "same"),
+			},
+			wantSpan: "09:00-11:00", wantDur: 2 * time.Hour, wantNotes: "same",
+		},
+		{
+			name:     "different activities",
+			sessions: []*Session{part("write", at(9, 0), at(10, 0), nil, ""), part("read", at(10, 0), at(11, 0), nil, "")},
+			wantErr:  true,
+		},
+		{
+			name:     "single session",
+			sessions: []*Session{part("write", at(9, 0), at(10, 0), nil, "")},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, err := Merge(tt.sessions)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if span(merged) != tt.wantSpan || merged.Duration != tt.wantDur {
+				t.Errorf("merged = %s (%v), want %s (%v)", span(merged), merged.Duration, tt.wantSpan, tt.wantDur)
+			}
+			if !reflect.DeepEqual(merged.Tags, tt.wantTags) {
+				t.Errorf("tags = %v, want %v", merged.Tags, tt.wantTags)
+			}
+			if merged.Notes != tt.wantNotes {
+				t.Errorf("notes = %q, want %q", merged.Notes, tt.wantNotes)
+			}
+		})
+	}
+}
+
+func TestMergeRefusesPomodoros(t *testing.T) {
+	plain := NewSessionAt("write", at(9, 0), at(9, 30))
+	pomodoro := NewSessionAt("write", at(9, 30), at(9, 55))
+	pomodoro.Pomodoro = true
+	if _, err := Merge([]*Session{plain, pomodoro}); err == nil {
+		t.Error("merging a completed pomodoro succeeded")
+	}
+}
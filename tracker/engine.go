@@ -0,0 +1,389 @@
+package tracker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Store persists finished sessions. *storage.Storage satisfies it.
+type Store interface {
+	SaveSession(sess *Session) error
+}
+
+// EventKind identifies what changed in an Engine
+type EventKind int
+
+const (
+	EventStarted    EventKind = iota // A session started running
+	EventStopped                     // A session stopped and was saved
+	EventPaused                      // A running session was paused
+	EventResumed                     // A paused session was resumed
+	EventThreshold                   // A session ran past the notification threshold
+	EventTick                        // The clock advanced
+	EventDayChanged                  // The clock crossed midnight
+	EventSplit                       // A running session was saved up to midnight and continues from there
+)
+
+// String returns a short name for the event kind
+func (k EventKind) String() string {
+	switch k {
+	case EventStarted:
+		return "started"
+	case EventStopped:
+		return "stopped"
+	case EventPaused:
+		return "paused"
+	case EventResumed:
+		return "resumed"
+	case EventThreshold:
+		return "threshold"
+	case EventTick:
+		return "tick"
+	case EventDayChanged:
+		return "day changed"
+	case EventSplit:
+		return "split"
+	default:
+		return "unknown"
+	}
+}
+
+// Event describes a state change published by an Engine. Session is nil for
+// clock events.
+type Event struct {
+	Kind    EventKind
+	Session *Session
+	Time    time.Time
+	Kept    bool // For EventResumed, whether the paused time counts as tracked
+}
+
+// TimerStatus is a running session as seen at one instant
+type TimerStatus struct {
+	Session  *Session
+	Elapsed  time.Duration
+	Paused   bool
+	PausedAt time.Time // When the current pause began, zero if not paused
+}
+
+// Engine owns the running sessions independently of any user interface. It
+// saves sessions when they stop, watches the clock for long-running sessions
+// and day changes, and publishes every state change to its subscribers.
+type Engine struct {
+	mu          sync.Mutex
+	store       Store
+	now         func() time.Time
+	threshold   time.Duration
+	running     []*Session
+	notified    map[*Session]bool
+	lastTick    time.Time
+	splitDays   bool // Save running sessions at midnight and continue them
+	subscribers map[int]func(Event)
+	nextSubID   int
+}
+
+// NewEngine creates an engine that saves stopped sessions to store and
+// publishes EventThreshold once a session has run for threshold (0 disables it)
+func NewEngine(store Store, threshold time.Duration) *Engine {
+	return &Engine{
+		store:       store,
+		now:         time.Now,
+		threshold:   threshold,
+		notified:    make(map[*Session]bool),
+		subscribers: make(map[int]func(Event)),
+	}
+}
+
+// SetClock replaces the engine's time source, e.g. with a fake clock
+func (e *Engine) SetClock(now func() time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.now = now
+}
+
+// SetThreshold changes the long-session notification threshold
+func (e *Engine) SetThreshold(threshold time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.threshold = threshold
+}
+
+// SetSplitAtMidnight decides whether running sessions are saved at midnight
+// and continue as a new session, so each day holds its own part
+func (e *Engine) SetSplitAtMidnight(split bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.splitDays = split
+}
+
+// Now returns the current time according to the engine's clock
+func (e *Engine) Now() time.Time {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.now()
+}
+
+// Subscribe registers fn to receive every event and returns a function that
+// unregisters it. Events are delivered synchronously, outside the engine's
+// lock, so fn may call back into the engine.
+func (e *Engine) Subscribe(fn func(Event)) (unsubscribe func()) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	id := e.nextSubID
+	e.nextSubID++
+	e.subscribers[id] = fn
+	return func() {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		delete(e.subscribers, id)
+	}
+}
+
+// publish delivers events to a snapshot of the subscribers. It must be called
+// without holding e.mu.
+func (e *Engine) publish(events ...Event) {
+	e.mu.Lock()
+	subs := make([]func(Event), 0, len(e.subscribers))
+	for _, fn := range e.subscribers {
+		subs = append(subs, fn)
+	}
+	e.mu.Unlock()
+	for _, ev := range events {
+		for _, fn := range subs {
+			fn(ev)
+		}
+	}
+}
+
+// Start begins tracking sess as of now, alongside any sessions already running
+func (e *Engine) Start(sess *Session) error {
+	e.mu.Lock()
+	now := e.now()
+	sess.StartTime = now
+	sess.EndTime = time.Time{}
+	if err := sess.Validate(); err != nil {
+		e.mu.Unlock()
+		return err
+	}
+	for _, r := range e.running {
+		if r == sess {
+			e.mu.Unlock()
+			return fmt.Errorf("session %q is already running", sess.Activity)
+		}
+	}
+	e.running = append(e.running, sess)
+	e.mu.Unlock()
+
+	e.publish(Event{Kind: EventStarted, Session: sess, Time: now})
+	return nil
+}
+
+// Stop ends a running session and saves it. If saving fails the session
+// keeps running so no tracked time is lost.
+func (e *Engine) Stop(sess *Session) error {
+	e.mu.Lock()
+	index := e.indexOf(sess)
+	if index < 0 {
+		e.mu.Unlock()
+		return fmt.Errorf("session %q is not running", sess.Activity)
+	}
+	now := e.now()
+	stopped := *sess
+	stopped.StopAt(now)
+	if err := stopped.Validate(); err != nil {
+		e.mu.Unlock()
+		return err
+	}
+	if err := e.store.SaveSession(&stopped); err != nil {
+		e.mu.Unlock()
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+	*sess = stopped
+	e.running = append(e.running[:index], e.running[index+1:]...)
+	delete(e.notified, sess)
+	e.mu.Unlock()
+
+	e.publish(Event{Kind: EventStopped, Session: sess, Time: now})
+	return nil
+}
+
+// StopAll stops every running session, returning the first error
+func (e *Engine) StopAll() error {
+	var firstErr error
+	for _, sess := range e.Running() {
+		if err := e.Stop(sess); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Pause stops the clock of a running session as of at
+func (e *Engine) Pause(sess *Session, at time.Time) {
+	e.mu.Lock()
+	if e.indexOf(sess) < 0 || sess.IsPaused() {
+		e.mu.Unlock()
+		return
+	}
+	sess.Pause(at)
+	e.mu.Unlock()
+	e.publish(Event{Kind: EventPaused, Session: sess, Time: at})
+}
+
+// Resume restarts a paused session; keep decides whether the paused time
+// counts as tracked. It returns how long the session was paused.
+func (e *Engine) Resume(sess *Session, at time.Time, keep bool) time.Duration {
+	e.mu.Lock()
+	if e.indexOf(sess) < 0 || !sess.IsPaused() {
+		e.mu.Unlock()
+		return 0
+	}
+	paused := sess.Resume(at, keep)
+	e.mu.Unlock()
+	e.publish(Event{Kind: EventResumed, Session: sess, Time: at, Kept: keep})
+	return paused
+}
+
+// SetNotes replaces the notes of a running session
+func (e *Engine) SetNotes(sess *Session, notes string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	sess.Notes = notes
+}
+
+// SetPomodoro marks whether a running session counts as a completed Pomodoro
+// interval once it stops
+func (e *Engine) SetPomodoro(sess *Session, completed bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	sess.Pomodoro = completed
+}
+
+// Notes returns the notes of a session, safe to call while it is running
+func (e *Engine) Notes(sess *Session) string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return sess.Notes
+}
+
+// Running returns the running sessions in start order
+func (e *Engine) Running() []*Session {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]*Session(nil), e.running...)
+}
+
+// IsTracking reports whether any session is running
+func (e *Engine) IsTracking() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return len(e.running) > 0
+}
+
+// Status returns the elapsed time and pause state of every running session
+func (e *Engine) Status() []TimerStatus {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	now := e.now()
+	status := make([]TimerStatus, len(e.running))
+	for i, s := range e.running {
+		status[i] = TimerStatus{Session: s, Elapsed: s.Elapsed(now), Paused: s.IsPaused(), PausedAt: s.PausedAt()}
+	}
+	return status
+}
+
+// Snapshots returns copies of the running sessions as if they ended now,
+// suitable for totals and goal progress
+func (e *Engine) Snapshots() []*Session {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	now := e.now()
+	snapshots := make([]*Session, len(e.running))
+	for i, s := range e.running {
+		snapshot := *s
+		snapshot.Duration = s.Elapsed(now)
+		snapshot.EndTime = now
+		snapshots[i] = &snapshot
+	}
+	return snapshots
+}
+
+// Tick advances the engine's view of the clock: it publishes EventTick,
+// EventDayChanged when midnight has passed since the last tick (splitting
+// running sessions there if enabled), and EventThreshold for sessions that
+// just crossed the threshold
+func (e *Engine) Tick() {
+	e.mu.Lock()
+	now := e.now()
+	events := []Event{{Kind: EventTick, Time: now}}
+	if !e.lastTick.IsZero() && !sameDate(e.lastTick, now) {
+		if e.splitDays {
+			events = append(events, e.splitRunning(DayStart(now))...)
+		}
+		events = append(events, Event{Kind: EventDayChanged, Time: now})
+	}
+	e.lastTick = now
+	if e.threshold > 0 {
+		for _, s := range e.running {
+			if !e.notified[s] && s.Elapsed(now) >= e.threshold {
+				e.notified[s] = true
+				events = append(events, Event{Kind: EventThreshold, Session: s, Time: now})
+			}
+		}
+	}
+	e.mu.Unlock()
+	e.publish(events...)
+}
+
+// Run calls Tick every interval until ctx is cancelled
+func (e *Engine) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			e.Tick()
+		}
+	}
+}
+
+// splitRunning saves the part of every running session before midnight and
+// lets the session continue from midnight. A session that cannot be saved
+// keeps running unsplit. The caller must hold e.mu.
+func (e *Engine) splitRunning(midnight time.Time) []Event {
+	var events []Event
+	for _, s := range e.running {
+		if !s.StartTime.Before(midnight) {
+			continue
+		}
+		restore := *s
+		done := s.carryOver(midnight)
+		if err := e.store.SaveSession(done); err != nil {
+			*s = restore
+			continue
+		}
+		events = append(events, Event{Kind: EventSplit, Session: s, Time: midnight})
+	}
+	return events
+}
+
+// indexOf returns the position of sess among the running sessions, or -1.
+// The caller must hold e.mu.
+func (e *Engine) indexOf(sess *Session) int {
+	for i, r := range e.running {
+		if r == sess {
+			return i
+		}
+	}
+	return -1
+}
+
+// sameDate reports whether a and b fall on the same calendar day
+func sameDate(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
@@ -0,0 +1,234 @@
+package tracker
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// fakeClock is a settable time source for the engine
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+// memStore keeps saved sessions in memory and fails while err is set
+type memStore struct {
+	saved []*Session
+	err   error
+}
+
+func (m *memStore) SaveSession(sess *Session) error {
+	if m.err != nil {
+		return m.err
+	}
+	saved := *sess
+	m.saved = append(m.saved, &saved)
+	return nil
+}
+
+// newTestEngine returns an engine on a fake clock starting at start, and
+// records the kinds of the events it publishes
+func newTestEngine(start time.Time, threshold time.Duration) (*Engine, *fakeClock, *memStore, *[]EventKind) {
+	clock := &fakeClock{t: start}
+	store := &memStore{}
+	e := NewEngine(store, threshold)
+	e.SetClock(clock.now)
+	var kinds []EventKind
+	e.Subscribe(func(ev Event) { kinds = append(kinds, ev.Kind) })
+	return e, clock, store, &kinds
+}
+
+func TestEngineStartStop(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.Local)
+	tests := []struct {
+		name        string
+		saveErr     error
+		wantErr     bool
+		wantRunning int
+		wantSaved   int
+		wantEvents  []EventKind
+	}{
+		{"saved", nil, false, 0, 1, []EventKind{EventStarted, EventStopped}},
+		{"save fails and keeps running", errors.New("disk full"), true, 1, 0, []EventKind{EventStarted}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, clock, store, kinds := newTestEngine(start, 0)
+			sess := NewSession("write")
+			if err := e.Start(sess); err != nil {
+				t.Fatalf("Start: %v", err)
+			}
+			if !sess.StartTime.Equal(start) {
+				t.Errorf("StartTime = %v, want %v", sess.StartTime, start)
+			}
+			clock.advance(30 * time.Minute)
+			store.err = tt.saveErr
+			err := e.Stop(sess)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Stop error = %v, want error %v", err, tt.wantErr)
+			}
+			if got := len(e.Running()); got != tt.wantRunning {
+				t.Errorf("running = %d, want %d", got, tt.wantRunning)
+			}
+			if got := len(store.saved); got != tt.wantSaved {
+				t.Fatalf("saved = %d, want %d", got, tt.wantSaved)
+			}
+			if tt.wantSaved > 0 && store.saved[0].Duration != 30*time.Minute {
+				t.Errorf("saved duration = %v, want 30m", store.saved[0].Duration)
+			}
+			if tt.wantErr && !sess.EndTime.IsZero() {
+				t.Errorf("failed stop changed the running session: EndTime = %v", sess.EndTime)
+			}
+			if !reflect.DeepEqual(*kinds, tt.wantEvents) {
+				t.Errorf("events = %v, want %v", *kinds, tt.wantEvents)
+			}
+		})
+	}
+}
+
+func TestEngineStartTwice(t *testing.T) {
+	e, _, _, _ := newTestEngine(time.Date(2026, 3, 2, 9, 0, 0, 0, time.Local), 0)
+	sess := NewSession("write")
+	if err := e.Start(sess); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if err := e.Start(sess); err == nil {
+		t.Error("starting a running session again succeeded")
+	}
+	if err := e.Stop(NewSession("other")); err == nil {
+		t.Error("stopping a session that is not running succeeded")
+	}
+}
+
+func TestEngineThresholdFiresOnce(t *testing.T) {
+	e, clock, _, kinds := newTestEngine(time.Date(2026, 3, 2, 9, 0, 0, 0, time.Local), time.Hour)
+	if err := e.Start(NewSession("write")); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	thresholds := func() int {
+		n := 0
+		for _, k := range *kinds {
+			if k == EventThreshold {
+				n++
+			}
+		}
+		return n
+	}
+	steps := []struct {
+		advance time.Duration
+		want    int
+	}{
+		{30 * time.Minute, 0},
+		{29 * time.Minute, 0},
+		{time.Minute, 1},
+		{time.Minute, 1},
+		{2 * time.Hour, 1},
+	}
+	for i, step := range steps {
+		clock.advance(step.advance)
+		e.Tick()
+		if got := thresholds(); got != step.want {
+			t.Errorf("step %d: threshold events = %d, want %d", i, got, step.want)
+		}
+	}
+}
+
+func TestEngineMidnightSplit(t *testing.T) {
+	start := time.Date(2026, 3, 2, 23, 0, 0, 0, time.Local)
+	midnight := time.Date(2026, 3, 3, 0, 0, 0, 0, time.Local)
+	tests := []struct {
+		name       string
+		splitDays  bool
+		wantSaved  int
+		wantStart  time.Time
+		wantEvents []EventKind
+	}{
+		{"split", true, 1, midnight, []EventKind{EventStarted, EventTick, EventTick, EventSplit, EventDayChanged}},
+		{"no split", false, 0, start, []EventKind{EventStarted, EventTick, EventTick, EventDayChanged}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, clock, store, kinds := newTestEngine(start, 0)
+			e.SetSplitAtMidnight(tt.splitDays)
+			sess := NewSession("write")
+			if err := e.Start(sess); err != nil {
+				t.Fatalf("Start: %v", err)
+			}
+			clock.advance(30 * time.Minute)
+			e.Tick()
+			clock.advance(time.Hour)
+			e.Tick()
+
+			if got := len(store.saved); got != tt.wantSaved {
+				t.Fatalf("saved = %d, want %d", got, tt.wantSaved)
+			}
+			if tt.wantSaved > 0 {
+				done := store.saved[0]
+				if !done.StartTime.Equal(start) || !done.EndTime.Equal(midnight) || done.Duration != time.Hour {
+					t.Errorf("saved part = %v to %v (%v), want %v to %v (1h)", done.StartTime, done.EndTime, done.Duration, start, midnight)
+				}
+			}
+			if !sess.StartTime.Equal(tt.wantStart) {
+				t.Errorf("running StartTime = %v, want %v", sess.StartTime, tt.wantStart)
+			}
+			if len(e.Running()) != 1 {
+				t.Errorf("the session stopped running at midnight")
+			}
+			if !reflect.DeepEqual(*kinds, tt.wantEvents) {
+				t.Errorf("events = %v, want %v", *kinds, tt.wantEvents)
+			}
+		})
+	}
+}
+
+func TestEngineSplitSaveFailureKeepsSession(t *testing.T) {
+	start := time.Date(2026, 3, 2, 23, 0, 0, 0, time.Local)
+	e, clock, store, kinds := newTestEngine(start, 0)
+	e.SetSplitAtMidnight(true)
+	sess := NewSession("write")
+	if err := e.Start(sess); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	e.Tick()
+	store.err = errors.New("disk full")
+	clock.advance(90 * time.Minute)
+	e.Tick()
+	if !sess.StartTime.Equal(start) {
+		t.Errorf("StartTime = %v, want the unsplit start %v", sess.StartTime, start)
+	}
+	want := []EventKind{EventStarted, EventTick, EventTick, EventDayChanged}
+	if !reflect.DeepEqual(*kinds, want) {
+		t.Errorf("events = %v, want %v", *kinds, want)
+	}
+}
+
+func TestEngineEventOrder(t *testing.T) {
+	e, clock, _, kinds := newTestEngine(time.Date(2026, 3, 2, 9, 0, 0, 0, time.Local), 0)
+	sess := NewSession("write")
+	if err := e.Start(sess); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	clock.advance(10 * time.Minute)
+	e.Tick()
+	e.Pause(sess, clock.t)
+	e.Pause(sess, clock.t) // Already paused: no event
+	clock.advance(5 * time.Minute)
+	if paused := e.Resume(sess, clock.t, false); paused != 5*time.Minute {
+		t.Errorf("Resume = %v, want 5m", paused)
+	}
+	clock.advance(10 * time.Minute)
+	if err := e.Stop(sess); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	want := []EventKind{EventStarted, EventTick, EventPaused, EventResumed, EventStopped}
+	if !reflect.DeepEqual(*kinds, want) {
+		t.Errorf("events = %v, want %v", *kinds, want)
+	}
+	if sess.Duration != 20*time.Minute {
+		t.Errorf("Duration = %v, want 20m without the pause", sess.Duration)
+	}
+}
@@ -0,0 +1,169 @@
+package tracker
+
+import (
+	"fmt"
+	"time"
+)
+
+// GoalKind distinguishes targets to reach from limits to stay under
+type GoalKind string
+
+const (
+	GoalMinimum GoalKind = "goal"   // e.g. at least 10h/week on study
+	GoalBudget  GoalKind = "budget" // e.g. client-x max 20h/month
+)
+
+// GoalScope selects which session field a goal is measured against
+type GoalScope string
+
+const (
+	ScopeCategory GoalScope = "category"
+	ScopeTag      GoalScope = "tag"
+	ScopeProject  GoalScope = "project"
+)
+
+// GoalPeriod is the calendar window a goal is evaluated over
+type GoalPeriod string
+
+const (
+	PeriodDay   GoalPeriod = "day"
+	PeriodWeek  GoalPeriod = "week"
+	PeriodMonth GoalPeriod = "month"
+)
+
+// Goal is a time target or budget for a category, tag or project
+type Goal struct {
+	Name   string     `json:"name"`
+	Kind   GoalKind   `json:"kind"`
+	Scope  GoalScope  `json:"scope"`
+	Target string     `json:"target"` // The category, tag or project name
+	Hours  float64    `json:"hours"`
+	Period GoalPeriod `json:"period"`
+}
+
+// Validate checks that the goal is complete
+func (g Goal) Validate() error {
+	if g.Target == "" {
+		return fmt.Errorf("goal target cannot be empty")
+	}
+	if g.Hours <= 0 {
+		return fmt.Errorf("goal hours must be greater than zero")
+	}
+	switch g.Kind {
+	case GoalMinimum, GoalBudget:
+	default:
+		return fmt.Errorf("unknown goal kind %q", g.Kind)
+	}
+	switch g.Scope {
+	case ScopeCategory, ScopeTag, ScopeProject:
+	default:
+		return fmt.Errorf("unknown goal scope %q", g.Scope)
+	}
+	switch g.Period {
+	case PeriodDay, PeriodWeek, PeriodMonth:
+	default:
+		return fmt.Errorf("unknown goal period %q", g.Period)
+	}
+	return nil
+}
+
+// Label returns a short description such as "study >= 10h/week"
+func (g Goal) Label() string {
+	op := ">="
+	if g.Kind == GoalBudget {
+		op = "<="
+	}
+	name := g.Name
+	if name == "" {
+		name = g.Target
+		if g.Scope == ScopeTag {
+			name = "#" + name
+		} else if g.Scope == ScopeProject {
+			name = "@" + name
+		}
+	}
+	return fmt.Sprintf("%s %s %gh/%s", name, op, g.Hours, g.Period)
+}
+
+// Matches reports whether a session counts toward the goal
+func (g Goal) Matches(s *Session) bool {
+	switch g.Scope {
+	case ScopeCategory:
+		return s.Category == g.Target
+	case ScopeProject:
+		return s.Project == g.Target
+	case ScopeTag:
+		for _, t := range s.Tags {
+			if t == g.Target {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// PeriodRange returns the start and end of the goal's period containing now.
+// Weeks start on Monday.
+func (g Goal) PeriodRange(now time.Time) (time.Time, time.Time) {
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch g.Period {
+	case PeriodWeek:
+		offset := (int(day.Weekday()) + 6) % 7
+		start := day.AddDate(0, 0, -offset)
+		return start, start.AddDate(0, 0, 7)
+	case PeriodMonth:
+		start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+		return start, start.AddDate(0, 1, 0)
+	default:
+		return day, day.AddDate(0, 0, 1)
+	}
+}
+
+// GoalProgress is a goal's state over one period
+type GoalProgress struct {
+	Goal        Goal
+	PeriodStart time.Time
+	Tracked     time.Duration
+}
+
+// Progress sums the matching time inside the goal's period; parts of sessions
+// outside the period, such as before midnight, are not counted
+func (g Goal) Progress(sessions []*Session, now time.Time) GoalProgress {
+	start, end := g.PeriodRange(now)
+	p := GoalProgress{Goal: g, PeriodStart: start}
+	for _, s := range ClipSessions(sessions, start, end) {
+		if g.Matches(s) {
+			p.Tracked += s.Duration
+		}
+	}
+	return p
+}
+
+// Fraction returns tracked time as a share of the goal's hours
+func (p GoalProgress) Fraction() float64 {
+	return p.Tracked.Hours() / p.Goal.Hours
+}
+
+// Met reports whether a minimum goal has been reached
+func (p GoalProgress) Met() bool {
+	return p.Goal.Kind == GoalMinimum && p.Fraction() >= 1
+}
+
+// Exceeded reports whether a budget has been overrun
+func (p GoalProgress) Exceeded() bool {
+	return p.Goal.Kind == GoalBudget && p.Fraction() > 1
+}
+
+// Status returns a one-word summary for reports
+func (p GoalProgress) Status() string {
+	switch {
+	case p.Met():
+		return "met"
+	case p.Exceeded():
+		return "over budget"
+	case p.Goal.Kind == GoalBudget:
+		return "within budget"
+	default:
+		return "in progress"
+	}
+}
@@ -0,0 +1,161 @@
+package tracker
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// habitHistoryDays bounds how far back streaks are computed
+const habitHistoryDays = 366
+
+// Habit is a daily minimum for a category, e.g. at least 30 minutes of
+// reading every weekday
+type Habit struct {
+	Name     string         `json:"name"`
+	Category string         `json:"category"`
+	Minutes  int            `json:"minutes"`
+	Days     []time.Weekday `json:"days,omitempty"` // Days the habit is due, empty for every day
+	Created  time.Time      `json:"created"`        // Days before this are not counted as missed
+}
+
+// HabitDay is one calendar day of a habit's history
+type HabitDay struct {
+	Date    time.Time
+	Due     bool
+	Met     bool
+	Tracked time.Duration
+}
+
+// HabitStatus is a habit's streaks and history up to today
+type HabitStatus struct {
+	Habit   Habit
+	Current int // Consecutive due days met, up to today
+	Longest int
+	Missed  int        // Due days not met, not counting today
+	Today   HabitDay   // Today, which may still be met
+	Days    []HabitDay // Oldest first, ending with today
+}
+
+// Validate checks that the habit is complete
+func (h Habit) Validate() error {
+	if strings.TrimSpace(h.Category) == "" {
+		return fmt.Errorf("habit category cannot be empty")
+	}
+	if h.Minutes <= 0 {
+		return fmt.Errorf("habit minutes must be greater than zero")
+	}
+	return nil
+}
+
+// Target returns the daily minimum as a duration
+func (h Habit) Target() time.Duration {
+	return time.Duration(h.Minutes) * time.Minute
+}
+
+// DueOn reports whether the habit applies on day
+func (h Habit) DueOn(day time.Time) bool {
+	if len(h.Days) == 0 {
+		return true
+	}
+	for _, d := range h.Days {
+		if d == day.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+// Label returns a short description such as "reading >= 30m weekdays"
+func (h Habit) Label() string {
+	name := h.Name
+	if name == "" {
+		name = h.Category
+	}
+	return fmt.Sprintf("%s >= %dm %s", name, h.Minutes, h.ScheduleLabel())
+}
+
+// ScheduleLabel names the days the habit is due
+func (h Habit) ScheduleLabel() string {
+	if len(h.Days) == 0 || len(h.Days) == 7 {
+		return "daily"
+	}
+	due := make(map[time.Weekday]bool)
+	for _, d := range h.Days {
+		due[d] = true
+	}
+	if len(due) == 5 && !due[time.Saturday] && !due[time.Sunday] {
+		return "weekdays"
+	}
+	var names []string
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if due[d] {
+			names = append(names, d.String()[:3])
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+// EvaluateHabit computes a habit's history from its creation day (at most a
+// year back) up to now. sessions must cover that range; each day counts the
+// time tracked in the habit's category on that day.
+func EvaluateHabit(h Habit, sessions []*Session, now time.Time) HabitStatus {
+	today := DayStart(now)
+	from := today.AddDate(0, 0, -habitHistoryDays+1)
+	if created := DayStart(h.Created); !h.Created.IsZero() && created.After(from) {
+		from = created
+	}
+	if from.After(today) {
+		// Created "in the future" after a clock change or a hand-edited config
+		from = today
+	}
+
+	var matching []*Session
+	for _, s := range sessions {
+		if strings.EqualFold(s.Category, h.Category) {
+			matching = append(matching, s)
+		}
+	}
+
+	status := HabitStatus{Habit: h}
+	run := 0
+	for day := from; !day.After(today); day = day.AddDate(0, 0, 1) {
+		tracked := TotalTracked(ClipSessions(matching, day, day.AddDate(0, 0, 1)), CountOnce)
+		d := HabitDay{Date: day, Due: h.DueOn(day), Tracked: tracked}
+		d.Met = d.Due && tracked >= h.Target()
+		status.Days = append(status.Days, d)
+		switch {
+		case !d.Due:
+			// Days off neither extend nor break a streak
+		case d.Met:
+			run++
+			if run > status.Longest {
+				status.Longest = run
+			}
+		case day.Equal(today):
+			// Today is not over yet, so it cannot break the streak
+		default:
+			run = 0
+			status.Missed++
+		}
+	}
+	status.Current = run
+	status.Today = status.Days[len(status.Days)-1]
+	return status
+}
+
+// HabitHistoryStart returns the earliest day EvaluateHabit looks at for any of habits
+func HabitHistoryStart(habits []Habit, now time.Time) time.Time {
+	earliest := DayStart(now).AddDate(0, 0, -habitHistoryDays+1)
+	start := DayStart(now)
+	for _, h := range habits {
+		created := DayStart(h.Created)
+		if h.Created.IsZero() || created.Before(earliest) {
+			return earliest
+		}
+		if created.Before(start) {
+			start = created
+		}
+	}
+	return start
+}
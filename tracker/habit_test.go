@@ -0,0 +1,37 @@
+package tracker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEvaluateHabitCreated(t *testing.T) {
+	now := time.Date(2026, 3, 4, 18, 0, 0, 0, time.Local)
+	reading := NewSessionAt("novel", time.Date(2026, 3, 4, 8, 0, 0, 0, time.Local), time.Date(2026, 3, 4, 8, 45, 0, 0, time.Local))
+	reading.Category = "reading"
+	tests := []struct {
+		name     string
+		created  time.Time
+		wantDays int
+	}{
+		{"never set", time.Time{}, habitHistoryDays},
+		{"two days ago", now.AddDate(0, 0, -2), 3},
+		{"today", now, 1},
+		{"after today", now.AddDate(0, 0, 3), 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := Habit{Category: "reading", Minutes: 30, Created: tt.created}
+			status := EvaluateHabit(h, []*Session{reading}, now)
+			if len(status.Days) != tt.wantDays {
+				t.Fatalf("days = %d, want %d", len(status.Days), tt.wantDays)
+			}
+			if !status.Today.Date.Equal(DayStart(now)) || !status.Today.Met {
+				t.Errorf("Today = %+v, want today and met", status.Today)
+			}
+			if status.Current != 1 {
+				t.Errorf("Current = %d, want 1", status.Current)
+			}
+		})
+	}
+}
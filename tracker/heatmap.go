@@ -0,0 +1,69 @@
+package tracker
+
+import (
+	"sort"
+	"time"
+)
+
+// HeatLevels is the number of non-empty intensity levels of a heatmap
+const HeatLevels = 4
+
+// DayTotal is the tracked time of one calendar day
+type DayTotal struct {
+	Date       time.Time
+	Total      time.Duration
+	ByCategory map[string]time.Duration
+}
+
+// Categories returns the day's categories, longest first
+func (d DayTotal) Categories() []string {
+	cats := make([]string, 0, len(d.ByCategory))
+	for c := range d.ByCategory {
+		cats = append(cats, c)
+	}
+	sort.Slice(cats, func(i, j int) bool {
+		if d.ByCategory[cats[i]] != d.ByCategory[cats[j]] {
+			return d.ByCategory[cats[i]] > d.ByCategory[cats[j]]
+		}
+		return cats[i] < cats[j]
+	})
+	return cats
+}
+
+// DailyTotals sums sessions per local calendar day, keyed by DayStart. Sessions
+// crossing midnight count toward each day they touch, and the day total
+// follows the overlap policy.
+func DailyTotals(sessions []*Session, policy OverlapPolicy) map[time.Time]DayTotal {
+	perDay := make(map[time.Time][]*Session)
+	for _, s := range sessions {
+		for _, part := range SplitByDay(s) {
+			day := DayStart(part.StartTime.Local())
+			perDay[day] = append(perDay[day], part)
+		}
+	}
+	totals := make(map[time.Time]DayTotal, len(perDay))
+	for day, parts := range perDay {
+		t := DayTotal{Date: day, Total: TotalTracked(parts, policy), ByCategory: make(map[string]time.Duration)}
+		for _, p := range parts {
+			t.ByCategory[p.Category] += p.Duration
+		}
+		totals[day] = t
+	}
+	return totals
+}
+
+// HeatLevel buckets a day's total into 0 (nothing tracked) to HeatLevels
+// relative to the busiest day, like quartiles of the maximum
+func HeatLevel(total, max time.Duration) int {
+	if total <= 0 || max <= 0 {
+		return 0
+	}
+	level := int((total*HeatLevels + max - 1) / max)
+	if level > HeatLevels {
+		level = HeatLevels
+	}
+	if level < 1 {
+		level = 1
+	}
+	return level
+}
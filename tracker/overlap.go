@@ -0,0 +1,119 @@
+package tracker
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// OverlapResolution selects how a new session is reconciled with existing ones
+type OverlapResolution int
+
+const (
+	// OverlapKeep saves the session unchanged, overlap included
+	OverlapKeep OverlapResolution = iota
+	// OverlapTrim moves the session's edges so it no longer overlaps
+	OverlapTrim
+	// OverlapSplit cuts the session into the gaps between existing sessions
+	OverlapSplit
+)
+
+// NewSessionAt creates a completed session with explicit start and end times
+// (used for manual and retroactive entries)
+func NewSessionAt(activity string, start, end time.Time) *Session {
+	sess := NewSession(activity)
+	sess.StartTime = start
+	sess.EndTime = end
+	sess.Duration = end.Sub(start)
+	return sess
+}
+
+// Overlaps reports whether two completed sessions share any time
+func (s *Session) Overlaps(other *Session) bool {
+	return s.StartTime.Before(other.EndTime) && other.StartTime.Before(s.EndTime)
+}
+
+// FindOverlaps returns the existing sessions that overlap s, ordered by start time
+func FindOverlaps(s *Session, existing []*Session) []*Session {
+	var overlaps []*Session
+	for _, e := range existing {
+		if e == s || (s.ID != 0 && e.ID == s.ID) {
+			continue
+		}
+		if s.Overlaps(e) {
+			overlaps = append(overlaps, e)
+		}
+	}
+	sort.Slice(overlaps, func(i, j int) bool {
+		return overlaps[i].StartTime.Before(overlaps[j].StartTime)
+	})
+	return overlaps
+}
+
+// ResolveOverlap reconciles s with the existing sessions and returns the
+// session(s) that should be saved
+func ResolveOverlap(s *Session, existing []*Session, mode OverlapResolution) ([]*Session, error) {
+	overlaps := FindOverlaps(s, existing)
+	if len(overlaps) == 0 || mode == OverlapKeep {
+		return []*Session{s}, nil
+	}
+
+	gaps := freeIntervals(s.StartTime, s.EndTime, overlaps)
+	if len(gaps) == 0 {
+		return nil, fmt.Errorf("session is fully covered by existing sessions")
+	}
+
+	switch mode {
+	case OverlapTrim:
+		// Trimming only moves the edges, so an existing session that falls
+		// strictly inside the new one leaves more than one gap
+		if len(gaps) > 1 {
+			return nil, fmt.Errorf("an existing session lies inside this entry; use split instead")
+		}
+		return []*Session{s.copyWithin(gaps[0][0], gaps[0][1])}, nil
+	case OverlapSplit:
+		parts := make([]*Session, 0, len(gaps))
+		for _, g := range gaps {
+			parts = append(parts, s.copyWithin(g[0], g[1]))
+		}
+		return parts, nil
+	default:
+		return nil, fmt.Errorf("unknown overlap resolution %d", mode)
+	}
+}
+
+// freeIntervals returns the parts of [start, end) not covered by the given
+// sessions, which must be sorted by start time
+func freeIntervals(start, end time.Time, busy []*Session) [][2]time.Time {
+	var gaps [][2]time.Time
+	cursor := start
+	for _, b := range busy {
+		if b.StartTime.After(cursor) {
+			gapEnd := b.StartTime
+			if gapEnd.After(end) {
+				gapEnd = end
+			}
+			if gapEnd.After(cursor) {
+				gaps = append(gaps, [2]time.Time{cursor, gapEnd})
+			}
+		}
+		if b.EndTime.After(cursor) {
+			cursor = b.EndTime
+		}
+	}
+	if end.After(cursor) {
+		gaps = append(gaps, [2]time.Time{cursor, end})
+	}
+	return gaps
+}
+
+// copyWithin returns a copy of s restricted to [start, end)
+func (s *Session) copyWithin(start, end time.Time) *Session {
+	c := *s
+	c.ID = 0
+	c.Tags = append([]string(nil), s.Tags...)
+	c.StartTime = start
+	c.EndTime = end
+	c.Duration = end.Sub(start)
+	return &c
+}
@@ -0,0 +1,77 @@
+package tracker
+
+import (
+	"testing"
+	"time"
+)
+
+// at returns 2026-03-02 at the given hour and minute
+func at(hour, minute int) time.Time {
+	return time.Date(2026, 3, 2, hour, minute, 0, 0, time.Local)
+}
+
+// span formats a session as "09:00-10:00" for comparisons
+func span(s *Session) string {
+	return s.StartTime.Format("15:04") + "-" + s.EndTime.Format("15:04")
+}
+
+func TestResolveOverlap(t *testing.T) {
+	existing := []*Session{
+		NewSessionAt("standup", at(9, 0), at(9, 30)),
+		NewSessionAt("lunch", at(12, 0), at(13, 0)),
+	}
+	tests := []struct {
+		name    string
+		start   time.Time
+		end     time.Time
+		mode    OverlapResolution
+		want    []string
+		wantErr bool
+	}{
+		{"no overlap", at(10, 0), at(11, 0), OverlapTrim, []string{"10:00-11:00"}, false},
+		{"keep", at(8, 30), at(9, 15), OverlapKeep, []string{"08:30-09:15"}, false},
+		{"trim end", at(8, 30), at(9, 15), OverlapTrim, []string{"08:30-09:00"}, false},
+		{"trim start", at(9, 15), at(10, 0), OverlapTrim, []string{"09:30-10:00"}, false},
+		{"touching edges are not overlaps", at(9, 30), at(12, 0), OverlapTrim, []string{"09:30-12:00"}, false},
+		{"trim around an inner session", at(11, 0), at(14, 0), OverlapTrim, nil, true},
+		{"split around an inner session", at(11, 0), at(14, 0), OverlapSplit, []string{"11:00-12:00", "13:00-14:00"}, false},
+		{"split across both", at(8, 0), at(14, 0), OverlapSplit, []string{"08:00-09:00", "09:30-12:00", "13:00-14:00"}, false},
+		{"fully covered", at(12, 15), at(12, 45), OverlapSplit, nil, true},
+		{"exactly covered", at(9, 0), at(9, 30), OverlapTrim, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSessionAt("write", tt.start, tt.end)
+			s.AddTags("draft")
+			got, err := ResolveOverlap(s, existing, tt.mode)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d sessions, want %v", len(got), tt.want)
+			}
+			for i, part := range got {
+				if span(part) != tt.want[i] {
+					t.Errorf("part %d = %s, want %s", i, span(part), tt.want[i])
+				}
+				if part.Duration != part.EndTime.Sub(part.StartTime) {
+					t.Errorf("part %d duration = %v, want its span", i, part.Duration)
+				}
+				if part.Activity != "write" || !hasTag(part.Tags, "draft") {
+					t.Errorf("part %d lost the activity or tags: %+v", i, part)
+				}
+			}
+		})
+	}
+}
+
+func TestResolveOverlapIgnoresItself(t *testing.T) {
+	s := NewSessionAt("write", at(9, 0), at(10, 0))
+	s.ID = 7
+	stored := NewSessionAt("write", at(9, 0), at(10, 0))
+	stored.ID = 7
+	got, err := ResolveOverlap(s, []*Session{s, stored}, OverlapTrim)
+	if err != nil || len(got) != 1 || span(got[0]) != "09:00-10:00" {
+		t.Errorf("editing a session overlapped itself: %v, %v", got, err)
+	}
+}
@@ -0,0 +1,128 @@
+package tracker
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PlannedBlock is a stretch of the day reserved for an activity
+type PlannedBlock struct {
+	ID       int64
+	Start    time.Time
+	End      time.Time
+	Activity string
+	Category string
+	Project  string
+	UID      string // Source event UID for blocks imported from a calendar
+}
+
+// NewPlannedBlock creates a block from the activity entry syntax, e.g. "study:math @thesis"
+func NewPlannedBlock(activity string, start, end time.Time) PlannedBlock {
+	s := NewSession(activity)
+	return PlannedBlock{Start: start, End: end, Activity: s.Activity, Category: s.Category, Project: s.Project}
+}
+
+// Validate checks that the block has an activity and a positive length
+func (b PlannedBlock) Validate() error {
+	if strings.TrimSpace(b.Activity) == "" && b.Category == "" {
+		return fmt.Errorf("planned block needs an activity")
+	}
+	if !b.End.After(b.Start) {
+		return fmt.Errorf("planned block must end after it starts")
+	}
+	return nil
+}
+
+// Length returns the planned duration
+func (b PlannedBlock) Length() time.Duration {
+	return b.End.Sub(b.Start)
+}
+
+// Label returns the block's activity in the entry syntax
+func (b PlannedBlock) Label() string {
+	return Template{Activity: b.Activity, Category: b.Category, Project: b.Project}.ActivityText()
+}
+
+// Matches reports whether a session counts toward the block: the category
+// must match when the block has one, and the activity when it has one. A
+// block without a category also accepts sessions whose category is the
+// block's activity, so "study" matches "study:math".
+func (b PlannedBlock) Matches(s *Session) bool {
+	if b.Category != "" {
+		return strings.EqualFold(b.Category, s.Category) &&
+			(b.Activity == "" || strings.EqualFold(b.Activity, s.Activity))
+	}
+	return strings.EqualFold(b.Activity, s.Activity) || strings.EqualFold(b.Activity, s.Category)
+}
+
+// BlockAdherence is how much of a planned block was spent as planned
+type BlockAdherence struct {
+	Block   PlannedBlock
+	Matched time.Duration // Tracked time on the planned activity within the block
+	Other   time.Duration // Tracked time on other activities within the block
+}
+
+// Percent returns the matched share of the block, 0 to 100
+func (a BlockAdherence) Percent() float64 {
+	if a.Block.Length() <= 0 {
+		return 0
+	}
+	return 100 * float64(a.Matched) / float64(a.Block.Length())
+}
+
+// AdherenceReport compares a day's planned blocks with what was tracked
+type AdherenceReport struct {
+	Blocks    []BlockAdherence
+	Planned   time.Duration // Total length of the blocks
+	Matched   time.Duration // Tracked as planned
+	Tracked   time.Duration // Everything tracked that day
+	Unplanned time.Duration // Tracked outside any block
+}
+
+// Percent returns the share of planned time that was tracked as planned, 0 to 100
+func (r AdherenceReport) Percent() float64 {
+	if r.Planned <= 0 {
+		return 0
+	}
+	return 100 * float64(r.Matched) / float64(r.Planned)
+}
+
+// CompareDay measures how closely sessions followed the planned blocks of
+// day. Concurrent sessions are counted once, so a block can never be more
+// than fully met.
+func CompareDay(blocks []PlannedBlock, sessions []*Session, day time.Time) AdherenceReport {
+	dayStart := DayStart(day)
+	dayEnd := dayStart.AddDate(0, 0, 1)
+	sessions = ClipSessions(sessions, dayStart, dayEnd)
+
+	var report AdherenceReport
+	report.Tracked = TotalTracked(sessions, CountOnce)
+	var inBlocks []*Session
+	for _, b := range blocks {
+		if !b.Start.Before(dayEnd) || !b.End.After(dayStart) {
+			continue
+		}
+		inside := ClipSessions(sessions, b.Start, b.End)
+		var matching []*Session
+		for _, s := range inside {
+			if b.Matches(s) {
+				matching = append(matching, s)
+			}
+		}
+		a := BlockAdherence{Block: b, Matched: TotalTracked(matching, CountOnce)}
+		a.Other = TotalTracked(inside, CountOnce) - a.Matched
+		if a.Other < 0 {
+			a.Other = 0
+		}
+		report.Blocks = append(report.Blocks, a)
+		report.Planned += b.Length()
+		report.Matched += a.Matched
+		inBlocks = append(inBlocks, inside...)
+	}
+	report.Unplanned = report.Tracked - TotalTracked(inBlocks, CountOnce)
+	if report.Unplanned < 0 {
+		report.Unplanned = 0
+	}
+	return report
+}
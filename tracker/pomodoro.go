@@ -0,0 +1,190 @@
+package tracker
+
+import (
+	"fmt"
+	"time"
+)
+
+// PomodoroPhase is one stage of the Pomodoro cycle
+type PomodoroPhase int
+
+const (
+	PhaseWork PomodoroPhase = iota
+	PhaseShortBreak
+	PhaseLongBreak
+)
+
+// String returns a display name for the phase
+func (p PomodoroPhase) String() string {
+	switch p {
+	case PhaseWork:
+		return "Work"
+	case PhaseShortBreak:
+		return "Short Break"
+	case PhaseLongBreak:
+		return "Long Break"
+	default:
+		return "Unknown"
+	}
+}
+
+// PomodoroSettings holds the phase lengths and how many work intervals come
+// before a long break
+type PomodoroSettings struct {
+	Work       time.Duration
+	ShortBreak time.Duration
+	LongBreak  time.Duration
+	Cycles     int
+}
+
+// Validate checks that all phases have a positive length
+func (ps PomodoroSettings) Validate() error {
+	if ps.Work <= 0 || ps.ShortBreak <= 0 || ps.LongBreak <= 0 {
+		return fmt.Errorf("pomodoro phases must be longer than zero")
+	}
+	if ps.Cycles < 1 {
+		return fmt.Errorf("pomodoro cycle count must be at least 1")
+	}
+	return nil
+}
+
+// Pomodoro tracks progress through work and break phases. Work phases are
+// turned into sessions for the given activity as they complete.
+type Pomodoro struct {
+	Settings   PomodoroSettings
+	Phase      PomodoroPhase
+	PhaseStart time.Time
+	Completed  int // Work intervals completed in the current set
+	Activity   string
+	Tags       []string
+	Paused     time.Duration // Time in the current phase that does not count toward it
+	pausedAt   time.Time
+}
+
+// NewPomodoro starts a Pomodoro run with a work phase beginning at now
+func NewPomodoro(settings PomodoroSettings, activity string, tags []string, now time.Time) *Pomodoro {
+	return &Pomodoro{
+		Settings:   settings,
+		Phase:      PhaseWork,
+		PhaseStart: now,
+		Activity:   activity,
+		Tags:       tags,
+	}
+}
+
+// PhaseLength returns the configured length of the current phase
+func (p *Pomodoro) PhaseLength() time.Duration {
+	switch p.Phase {
+	case PhaseShortBreak:
+		return p.Settings.ShortBreak
+	case PhaseLongBreak:
+		return p.Settings.LongBreak
+	default:
+		return p.Settings.Work
+	}
+}
+
+// Remaining returns how long is left in the current phase
+func (p *Pomodoro) Remaining(now time.Time) time.Duration {
+	left := p.PhaseLength() - p.elapsed(now)
+	if left < 0 {
+		return 0
+	}
+	return left
+}
+
+// elapsed returns how much of the current phase has counted as of now
+func (p *Pomodoro) elapsed(now time.Time) time.Duration {
+	if p.IsPaused() {
+		now = p.pausedAt
+	}
+	return now.Sub(p.PhaseStart) - p.Paused
+}
+
+// Pause stops the phase clock at at, e.g. while the user is idle
+func (p *Pomodoro) Pause(at time.Time) {
+	if p.IsPaused() {
+		return
+	}
+	if at.Before(p.PhaseStart) {
+		at = p.PhaseStart
+	}
+	p.pausedAt = at
+}
+
+// IsPaused reports whether the phase clock is paused
+func (p *Pomodoro) IsPaused() bool {
+	return !p.pausedAt.IsZero()
+}
+
+// Resume restarts the phase clock. If keep is true the paused time still
+// counts toward the phase, as it does for the work session.
+func (p *Pomodoro) Resume(at time.Time, keep bool) {
+	if !p.IsPaused() {
+		return
+	}
+	if paused := at.Sub(p.pausedAt); !keep && paused > 0 {
+		p.Paused += paused
+	}
+	p.pausedAt = time.Time{}
+}
+
+// Advance ends the current phase at now and moves to the next one. When a
+// work phase ends, the interval is returned as a session, marked as a
+// pomodoro only if it ran for its full length (skipping early does not count).
+func (p *Pomodoro) Advance(now time.Time) (worked *Session) {
+	if p.Phase != PhaseWork {
+		p.Phase = PhaseWork
+		p.startPhase(now)
+		return nil
+	}
+
+	worked = p.workSession(now)
+	if worked.Pomodoro {
+		p.Completed++
+	}
+	if p.Completed >= p.Settings.Cycles {
+		p.Phase = PhaseLongBreak
+		p.Completed = 0
+	} else {
+		p.Phase = PhaseShortBreak
+	}
+	p.startPhase(now)
+	return worked
+}
+
+// startPhase starts the clock of the phase that begins at now
+func (p *Pomodoro) startPhase(now time.Time) {
+	p.PhaseStart = now
+	p.Paused = 0
+	p.pausedAt = time.Time{}
+}
+
+// Stop ends the run, returning the partial work interval if one was in progress
+func (p *Pomodoro) Stop(now time.Time) *Session {
+	if p.Phase != PhaseWork || !now.After(p.PhaseStart) {
+		return nil
+	}
+	return p.workSession(now)
+}
+
+// workSession builds the session for the work phase ending at now
+func (p *Pomodoro) workSession(now time.Time) *Session {
+	sess := NewSessionAt(p.Activity, p.PhaseStart, now)
+	sess.Tags = append(sess.Tags, p.Tags...)
+	sess.Duration = p.elapsed(now)
+	sess.PausedDuration = now.Sub(p.PhaseStart) - sess.Duration
+	sess.Pomodoro = sess.Duration >= p.Settings.Work
+	return sess
+}
+
+// CountPomodoros returns how many of the sessions are completed pomodoros
+func CountPomodoros(sessions []*Session) int {
+	count := 0
+	for _, s := range sessions {
+		if s.Pomodoro {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,51 @@
+package tracker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPomodoroIdlePause(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.Local)
+	settings := PomodoroSettings{Work: 25 * time.Minute, ShortBreak: 5 * time.Minute, LongBreak: 15 * time.Minute, Cycles: 4}
+	tests := []struct {
+		name          string
+		keep          bool
+		wantRemaining time.Duration // At 09:25, after a ten minute pause
+		wantCompleted bool          // When the work phase ends at 09:35
+	}{
+		{"discarded", false, 10 * time.Minute, true},
+		{"kept", true, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPomodoro(settings, "write", nil, start)
+			p.Pause(start.Add(10 * time.Minute))
+			if got := p.Remaining(start.Add(15 * time.Minute)); got != 15*time.Minute {
+				t.Errorf("Remaining while paused = %v, want 15m", got)
+			}
+			p.Resume(start.Add(20*time.Minute), tt.keep)
+			if got := p.Remaining(start.Add(25 * time.Minute)); got != tt.wantRemaining {
+				t.Errorf("Remaining = %v, want %v", got, tt.wantRemaining)
+			}
+			worked := p.Advance(start.Add(35 * time.Minute))
+			if worked.Pomodoro != tt.wantCompleted {
+				t.Errorf("Pomodoro = %v, want %v", worked.Pomodoro, tt.wantCompleted)
+			}
+			if p.Paused != 0 || p.IsPaused() {
+				t.Errorf("the break inherited the work phase's pause")
+			}
+		})
+	}
+}
+
+func TestPomodoroPauseDoesNotCompleteEarly(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.Local)
+	p := NewPomodoro(PomodoroSettings{Work: 25 * time.Minute, ShortBreak: 5 * time.Minute, LongBreak: 15 * time.Minute, Cycles: 4}, "write", nil, start)
+	p.Pause(start.Add(10 * time.Minute))
+	p.Resume(start.Add(20*time.Minute), false)
+	worked := p.Advance(start.Add(25 * time.Minute))
+	if worked.Pomodoro || worked.Duration != 15*time.Minute {
+		t.Errorf("worked = %v (pomodoro %v), want 15m and not completed", worked.Duration, worked.Pomodoro)
+	}
+}
@@ -0,0 +1,174 @@
+package tracker
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// RuleSyntax selects how a rule's pattern is matched against the activity
+type RuleSyntax string
+
+const (
+	RuleGlob  RuleSyntax = "glob"  // e.g. "standup*", matched with path.Match
+	RuleRegex RuleSyntax = "regex" // e.g. "^(code )?review"
+)
+
+// Rule fills in the category, tags, project or billable flag of sessions
+// whose activity matches a pattern. Matching ignores case.
+type Rule struct {
+	Name     string     `json:"name"`
+	Pattern  string     `json:"pattern"`
+	Syntax   RuleSyntax `json:"syntax"`             // Defaults to glob
+	Activity string     `json:"activity,omitempty"` // Canonical activity name to use instead
+	Category string     `json:"category,omitempty"`
+	Tags     []string   `json:"tags,omitempty"`
+	Project  string     `json:"project,omitempty"`
+	Billable bool       `json:"billable,omitempty"`
+}
+
+// RuleSet is a compiled, ordered list of rules
+type RuleSet struct {
+	rules   []Rule
+	regexps []*regexp.Regexp // nil for glob rules
+}
+
+// RuleChange is a session before and after applying rules to it
+type RuleChange struct {
+	Before *Session
+	After  *Session
+}
+
+var (
+	defaultRulesMu sync.RWMutex
+	defaultRules   *RuleSet
+)
+
+// NewRuleSet compiles the rules, reporting the first invalid pattern
+func NewRuleSet(rules []Rule) (*RuleSet, error) {
+	rs := &RuleSet{rules: rules, regexps: make([]*regexp.Regexp, len(rules))}
+	for i, r := range rules {
+		if r.Pattern == "" {
+			return nil, fmt.Errorf("rule %d: pattern cannot be empty", i+1)
+		}
+		switch r.Syntax {
+		case RuleRegex:
+			re, err := regexp.Compile("(?i)" + r.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("rule %d: %w", i+1, err)
+			}
+			rs.regexps[i] = re
+		case RuleGlob, "":
+			if _, err := path.Match(strings.ToLower(r.Pattern), ""); err != nil {
+				return nil, fmt.Errorf("rule %d: invalid glob %q", i+1, r.Pattern)
+			}
+		default:
+			return nil, fmt.Errorf("rule %d: unknown syntax %q", i+1, r.Syntax)
+		}
+	}
+	return rs, nil
+}
+
+// Rules returns the rules in the set
+func (rs *RuleSet) Rules() []Rule {
+	return rs.rules
+}
+
+// SetDefaultRules installs the rules NewSession applies to every new session;
+// nil disables them
+func SetDefaultRules(rs *RuleSet) {
+	defaultRulesMu.Lock()
+	defer defaultRulesMu.Unlock()
+	defaultRules = rs
+}
+
+// applyDefaultRules applies the installed rules, if any, to a new session
+func applyDefaultRules(s *Session) {
+	defaultRulesMu.RLock()
+	rs := defaultRules
+	defaultRulesMu.RUnlock()
+	if rs != nil {
+		rs.Apply(s)
+	}
+}
+
+// matches reports whether rule i matches the activity
+func (rs *RuleSet) matches(i int, activity string) bool {
+	if re := rs.regexps[i]; re != nil {
+		return re.MatchString(activity)
+	}
+	ok, _ := path.Match(strings.ToLower(rs.rules[i].Pattern), strings.ToLower(activity))
+	return ok
+}
+
+// Apply runs every matching rule over the session in order and reports
+// whether it changed. Rules only fill in what is missing: a category or
+// project already set wins over a rule, tags are added, and billable can
+// only be switched on.
+func (rs *RuleSet) Apply(s *Session) bool {
+	changed := false
+	activity := strings.TrimSpace(s.Activity)
+	for i, r := range rs.rules {
+		if !rs.matches(i, activity) {
+			continue
+		}
+		if r.Activity != "" && s.Activity != r.Activity {
+			s.Activity = r.Activity
+			changed = true
+		}
+		if r.Category != "" && s.Category == "" {
+			s.Category = r.Category
+			changed = true
+		}
+		if r.Project != "" && s.Project == "" {
+			s.Project = r.Project
+			changed = true
+		}
+		if s.AddTags(r.Tags...) {
+			changed = true
+		}
+		if r.Billable && !s.Billable {
+			s.Billable = true
+			changed = true
+		}
+	}
+	return changed
+}
+
+// Preview applies the rules to copies of sessions and returns those that
+// would change, leaving the sessions themselves untouched
+func (rs *RuleSet) Preview(sessions []*Session) []RuleChange {
+	var changes []RuleChange
+	for _, s := range sessions {
+		after := *s
+		after.Tags = append([]string(nil), s.Tags...)
+		if rs.Apply(&after) {
+			changes = append(changes, RuleChange{Before: s, After: &after})
+		}
+	}
+	return changes
+}
+
+// AddTags adds the tags the session does not have yet and reports whether any were added
+func (s *Session) AddTags(tags ...string) bool {
+	added := false
+	for _, tag := range tags {
+		if !hasTag(s.Tags, tag) {
+			s.Tags = append(s.Tags, tag)
+			added = true
+		}
+	}
+	return added
+}
+
+// hasTag reports whether tags contains tag, ignoring case
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package tracker
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRuleSetApply(t *testing.T) {
+	rs, err := NewRuleSet([]Rule{
+		{Name: "meetings", Pattern: "standup*", Category: "meetings", Tags: []string{"team"}},
+		{Name: "reviews", Pattern: `^(code )?review`, Syntax: RuleRegex, Activity: "Code review", Category: "dev", Project: "katana", Billable: true},
+		{Name: "all", Pattern: "*", Tags: []string{"auto"}},
+	})
+	if err != nil {
+		t.Fatalf("NewRuleSet: %v", err)
+	}
+	tests := []struct {
+		name         string
+		activity     string
+		category     string
+		tags         []string
+		wantActivity string
+		wantCategory string
+		wantProject  string
+		wantTags     []string
+		wantBillable bool
+	}{
+		{"glob ignores case", "Standup daily", "", nil, "Standup daily", "meetings", "", []string{"team", "auto"}, false},
+		{"regex renames the activity", "code REVIEW #12", "", nil, "Code review", "dev", "katana", []string{"auto"}, true},
+		{"set category wins", "standup", "personal", nil, "standup", "personal", "", []string{"team", "auto"}, false},
+		{"tags are not repeated", "standup", "", []string{"Team"}, "standup", "meetings", "", []string{"Team", "auto"}, false},
+		{"only the catch-all", "reading", "", nil, "reading", "", "", []string{"auto"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSessionAt(tt.activity, at(9, 0), at(10, 0))
+			s.Category = tt.category
+			s.Tags = tt.tags
+			if !rs.Apply(s) {
+				t.Fatal("Apply reported no change")
+			}
+			got := []any{s.Activity, s.Category, s.Project, s.Billable}
+			want := []any{tt.wantActivity, tt.wantCategory, tt.wantProject, tt.wantBillable}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("activity, category, project, billable = %v, want %v", got, want)
+			}
+			if !reflect.DeepEqual(s.Tags, tt.wantTags) {
+				t.Errorf("tags = %v, want %v", s.Tags, tt.wantTags)
+			}
+			if rs.Apply(s) {
+				t.Error("applying the rules twice changed the session again")
+			}
+		})
+	}
+}
+
+func TestRuleSetPreviewLeavesSessions(t *testing.T) {
+	rs, err := NewRuleSet([]Rule{{Pattern: "standup*", Category: "meetings", Tags: []string{"team"}}})
+	if err != nil {
+		t.Fatalf("NewRuleSet: %v", err)
+	}
+	standup := NewSessionAt("standup", at(9, 0), at(9, 15))
+	standup.Tags = []string{"daily"}
+	done := NewSessionAt("standup", at(9, 0), at(9, 15))
+	done.Category, done.Tags = "meetings", []string{"team"}
+	changes := rs.Preview([]*Session{standup, done, NewSessionAt("reading", at(10, 0), at(11, 0))})
+	if len(changes) != 1 || changes[0].Before != standup {
+		t.Fatalf("changes = %+v, want only the uncategorized standup", changes)
+	}
+	if standup.Category != "" || !reflect.DeepEqual(standup.Tags, []string{"daily"}) {
+		t.Errorf("Preview changed the session: %q %v", standup.Category, standup.Tags)
+	}
+	if changes[0].After.Category != "meetings" || !reflect.DeepEqual(changes[0].After.Tags, []string{"daily", "team"}) {
+		t.Errorf("After = %q %v", changes[0].After.Category, changes[0].After.Tags)
+	}
+}
+
+func TestNewRuleSetInvalid(t *testing.T) {
+	for _, r := range []Rule{
+		{Pattern: ""},
+		{Pattern: "[", Syntax: RuleGlob},
+		{Pattern: "(", Syntax: RuleRegex},
+		{Pattern: "x", Syntax: "sql"},
+	} {
+		if _, err := NewRuleSet([]Rule{r}); err == nil {
+			t.Errorf("NewRuleSet(%+v) succeeded", r)
+		}
+	}
+}
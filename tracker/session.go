@@ -15,12 +15,20 @@ type Session struct {
 	Activity  string
 	Category  string
 	Tags      []string
+	Project   string
+	Billable  bool
+	Pomodoro  bool   // True for a completed Pomodoro work interval
+	Notes     string // Free-form, possibly multi-line description of the work done
+	// PausedDuration is time inside the session that is not counted as tracked
+	PausedDuration time.Duration
+	pausedAt       time.Time
 }
 
 // NewSession creates a new tracking session
 func NewSession(activity string) *Session {
-	// Parse activity string for category and tags (e.g., "study:math #important")
+	// Parse activity string for category, tags and project (e.g., "study:math #important @thesis")
 	category := ""
+	project := ""
 	tags := []string{}
 
 	// Extract category (e.g., "study:math" -> category="study")
@@ -29,30 +37,93 @@ func NewSession(activity string) *Session {
 		activity = activity[idx+1:]
 	}
 
-	// Extract tags (words starting with #)
+	// Extract tags (words starting with #) and project (word starting with @)
 	words := strings.Fields(activity)
 	cleanActivity := []string{}
 
 	for _, word := range words {
 		if strings.HasPrefix(word, "#") {
 			tags = append(tags, word[1:])
+		} else if strings.HasPrefix(word, "@") && len(word) > 1 {
+			project = word[1:]
 		} else {
 			cleanActivity = append(cleanActivity, word)
 		}
 	}
 
-	return &Session{
+	sess := &Session{
 		StartTime: time.Now(),
 		Activity:  strings.Join(cleanActivity, " "),
 		Category:  category,
 		Tags:      tags,
+		Project:   project,
 	}
+	// Auto-categorization rules fill in whatever the text left out
+	applyDefaultRules(sess)
+	return sess
 }
 
 // Stop ends the current tracking session
 func (s *Session) Stop() {
-	s.EndTime = time.Now()
-	s.Duration = s.EndTime.Sub(s.StartTime)
+	s.StopAt(time.Now())
+}
+
+// StopAt ends the session at the given time
+func (s *Session) StopAt(at time.Time) {
+	s.EndTime = at
+	if s.IsPaused() {
+		s.Resume(s.EndTime, false)
+	}
+	s.Duration = s.EndTime.Sub(s.StartTime) - s.PausedDuration
+}
+
+// Pause stops the session's clock as of the given time (e.g. when the user went idle)
+func (s *Session) Pause(at time.Time) {
+	if s.IsPaused() {
+		return
+	}
+	if at.Before(s.StartTime) {
+		at = s.StartTime
+	}
+	s.pausedAt = at
+}
+
+// IsPaused reports whether the session is currently paused
+func (s *Session) IsPaused() bool {
+	return !s.pausedAt.IsZero()
+}
+
+// PausedAt returns when the current pause began, or the zero time if not paused
+func (s *Session) PausedAt() time.Time {
+	return s.pausedAt
+}
+
+// Resume restarts the clock and returns how long the session was paused.
+// If keep is true the paused time still counts as tracked time.
+func (s *Session) Resume(at time.Time, keep bool) time.Duration {
+	if !s.IsPaused() {
+		return 0
+	}
+	paused := at.Sub(s.pausedAt)
+	if paused < 0 {
+		paused = 0
+	}
+	if !keep {
+		s.PausedDuration += paused
+	}
+	s.pausedAt = time.Time{}
+	return paused
+}
+
+// Elapsed returns the tracked time of a running session as of now
+func (s *Session) Elapsed(now time.Time) time.Duration {
+	if !s.EndTime.IsZero() {
+		return s.Duration
+	}
+	if s.IsPaused() {
+		now = s.pausedAt
+	}
+	return now.Sub(s.StartTime) - s.PausedDuration
 }
 
 // Validate checks if the session data is valid
@@ -69,6 +140,18 @@ func (s *Session) Validate() error {
 	return nil
 }
 
+// MatchesQuery reports whether every word of query appears, ignoring case, in
+// the session's activity, notes, category, project or tags
+func (s *Session) MatchesQuery(query string) bool {
+	text := strings.ToLower(strings.Join([]string{s.Activity, s.Notes, s.Category, s.Project, strings.Join(s.Tags, " ")}, "\n"))
+	for _, word := range strings.Fields(strings.ToLower(query)) {
+		if !strings.Contains(text, word) {
+			return false
+		}
+	}
+	return true
+}
+
 // GetFormattedDuration returns a human-readable duration string
 func (s *Session) GetFormattedDuration() string {
 	if s.Duration == 0 {
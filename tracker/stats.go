@@ -0,0 +1,96 @@
+package tracker
+
+import (
+	"sort"
+	"time"
+)
+
+// RangeStats summarises the sessions of a date range for reports
+type RangeStats struct {
+	Start      time.Time
+	End        time.Time
+	Days       []DayTotal // One entry per calendar day in the range, oldest first
+	Total      time.Duration
+	ByCategory map[string]time.Duration
+	ByTag      map[string]time.Duration
+	ByHour     [24]time.Duration // Tracked time per hour of the day
+}
+
+// TagTotal is the time tracked with one tag
+type TagTotal struct {
+	Tag   string
+	Total time.Duration
+}
+
+// ComputeRangeStats aggregates sessions over the days from start up to, but
+// not including, end. Sessions are clipped to the range; the total follows the
+// overlap policy while the breakdowns add up every session in full.
+func ComputeRangeStats(sessions []*Session, start, end time.Time, policy OverlapPolicy) RangeStats {
+	start, end = DayStart(start), DayStart(end)
+	sessions = ClipSessions(sessions, start, end)
+	st := RangeStats{
+		Start:      start,
+		End:        end,
+		ByCategory: make(map[string]time.Duration),
+		ByTag:      make(map[string]time.Duration),
+	}
+
+	totals := DailyTotals(sessions, policy)
+	for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+		t, ok := totals[day]
+		if !ok {
+			t = DayTotal{Date: day, ByCategory: map[string]time.Duration{}}
+		}
+		st.Days = append(st.Days, t)
+		st.Total += t.Total
+	}
+
+	for _, s := range sessions {
+		st.ByCategory[s.Category] += s.Duration
+		for _, tag := range s.Tags {
+			st.ByTag[tag] += s.Duration
+		}
+		local := s.StartTime.Local()
+		hour := time.Date(local.Year(), local.Month(), local.Day(), local.Hour(), 0, 0, 0, time.Local)
+		for ; hour.Before(s.EndTime); hour = hour.Add(time.Hour) {
+			if part := s.Clip(hour, hour.Add(time.Hour)); part != nil {
+				st.ByHour[hour.Hour()] += part.Duration
+			}
+		}
+	}
+	return st
+}
+
+// Categories returns the range's categories, longest first
+func (r RangeStats) Categories() []string {
+	return DayTotal{ByCategory: r.ByCategory}.Categories()
+}
+
+// TopTags returns up to n tags ranked by tracked time; n <= 0 returns all
+func (r RangeStats) TopTags(n int) []TagTotal {
+	tags := make([]TagTotal, 0, len(r.ByTag))
+	for tag, d := range r.ByTag {
+		tags = append(tags, TagTotal{Tag: tag, Total: d})
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].Total != tags[j].Total {
+			return tags[i].Total > tags[j].Total
+		}
+		return tags[i].Tag < tags[j].Tag
+	})
+	if n > 0 && len(tags) > n {
+		tags = tags[:n]
+	}
+	return tags
+}
+
+// MaxDay returns the longest day total in the range
+func (r RangeStats) MaxDay() time.Duration {
+	var max time.Duration
+	for _, d := range r.Days {
+		if d.Total > max {
+			max = d.Total
+		}
+	}
+	return max
+}
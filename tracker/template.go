@@ -0,0 +1,109 @@
+package tracker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Template is a saved activity that can be started with one click
+type Template struct {
+	Name             string   `json:"name"`
+	Activity         string   `json:"activity"`
+	Category         string   `json:"category"`
+	Tags             []string `json:"tags"`
+	Project          string   `json:"project"`
+	Billable         bool     `json:"billable"`
+	EstimateMinutes  int      `json:"estimate_minutes"`  // Expected length, 0 for none
+	CountdownMinutes int      `json:"countdown_minutes"` // Countdown started alongside the timer, 0 for none
+}
+
+// TemplateFromText builds a template from the activity entry syntax, e.g.
+// "study:math #exam @thesis"
+func TemplateFromText(name, activity string, tags []string) Template {
+	s := NewSession(activity)
+	return Template{
+		Name:     name,
+		Activity: s.Activity,
+		Category: s.Category,
+		Tags:     append(s.Tags, tags...),
+		Project:  s.Project,
+	}
+}
+
+// Validate checks that the template can start a session
+func (t Template) Validate() error {
+	if strings.TrimSpace(t.Activity) == "" {
+		return fmt.Errorf("template activity cannot be empty")
+	}
+	if t.EstimateMinutes < 0 || t.CountdownMinutes < 0 {
+		return fmt.Errorf("template estimate and countdown cannot be negative")
+	}
+	return nil
+}
+
+// Label returns the template name, or its activity text when unnamed
+func (t Template) Label() string {
+	if t.Name != "" {
+		return t.Name
+	}
+	return t.ActivityText()
+}
+
+// ActivityText renders the template in the activity entry syntax
+func (t Template) ActivityText() string {
+	text := t.Activity
+	if t.Category != "" {
+		text = t.Category + ":" + text
+	}
+	if t.Project != "" {
+		text += " @" + t.Project
+	}
+	return text
+}
+
+// Estimate returns the expected session length, 0 for none
+func (t Template) Estimate() time.Duration {
+	return time.Duration(t.EstimateMinutes) * time.Minute
+}
+
+// Countdown returns the length of the countdown to start, 0 for none
+func (t Template) Countdown() time.Duration {
+	return time.Duration(t.CountdownMinutes) * time.Minute
+}
+
+// NewSession creates a session from the template, ready to be started
+func (t Template) NewSession() *Session {
+	sess := &Session{
+		StartTime: time.Now(),
+		Activity:  t.Activity,
+		Category:  t.Category,
+		Tags:      append([]string{}, t.Tags...),
+		Project:   t.Project,
+		Billable:  t.Billable,
+	}
+	applyDefaultRules(sess)
+	return sess
+}
+
+// RecentActivities returns the distinct activities of sessions in the entry
+// syntax, most recently started first, at most limit of them
+func RecentActivities(sessions []*Session, limit int) []string {
+	sorted := append([]*Session(nil), sessions...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].StartTime.After(sorted[j].StartTime) })
+	seen := make(map[string]bool)
+	var recent []string
+	for _, s := range sorted {
+		text := Template{Activity: s.Activity, Category: s.Category, Project: s.Project}.ActivityText()
+		if s.Activity == "" || seen[text] {
+			continue
+		}
+		seen[text] = true
+		recent = append(recent, text)
+		if len(recent) == limit {
+			break
+		}
+	}
+	return recent
+}
@@ -0,0 +1,300 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"katana/export"
+	"katana/storage"
+	"katana/tracker"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showBillingDialog manages clients and projects and generates invoices
+func (ui *MainUI) showBillingDialog() {
+	win := fyne.CurrentApp().Driver().AllWindows()[0]
+	grey := color.RGBA{R: 180, G: 180, B: 180, A: 255}
+
+	var clients []tracker.Client
+	var projects []tracker.Project
+	var clientList, projectList *widget.List
+	var clientSelect, invoiceClientSelect *widget.Select
+
+	clientNames := func() []string {
+		names := make([]string, len(clients))
+		for i, c := range clients {
+			names[i] = c.Name
+		}
+		return names
+	}
+	clientByName := func(name string) (tracker.Client, bool) {
+		for _, c := range clients {
+			if c.Name == name {
+				return c, true
+			}
+		}
+		return tracker.Client{}, false
+	}
+	reload := func() {
+		clients, _ = ui.storage.ListClients()
+		projects, _ = ui.storage.ListProjects()
+		clientList.Refresh()
+		projectList.Refresh()
+		clientSelect.Options = clientNames()
+		clientSelect.Refresh()
+		invoiceClientSelect.Options = clientNames()
+		invoiceClientSelect.Refresh()
+	}
+	showErr := func(err error) {
+		dialog.NewError(err, win).Show()
+	}
+
+	// --- Clients ---
+	clientList = widget.NewList(
+		func() int { return len(clients) },
+		func() fyne.CanvasObject {
+			label := canvas.NewText("", grey)
+			label.TextStyle = fyne.TextStyle{Monospace: true}
+			return container.NewBorder(nil, nil, nil, NewTerminalButton("Delete", nil), label)
+		},
+		func(i int, o fyne.CanvasObject) {
+			if i >= len(clients) {
+				return
+			}
+			c := clients[i]
+			row := o.(*fyne.Container)
+			label := row.Objects[0].(*canvas.Text)
+			label.Text = fmt.Sprintf("%s | %.2f %s/h", c.Name, c.HourlyRate, c.Currency)
+			canvas.Refresh(label)
+			row.Objects[1].(*TerminalButton).OnTap = func() {
+				if err := ui.storage.DeleteClient(c.ID); err != nil {
+					showErr(err)
+				}
+				reload()
+			}
+		},
+	)
+	clientName := widget.NewEntry()
+	clientName.SetPlaceHolder("Name")
+	clientCurrency := widget.NewEntry()
+	clientCurrency.SetPlaceHolder("Currency (EUR)")
+	clientRate := widget.NewEntry()
+	clientRate.SetPlaceHolder("Hourly rate")
+	clientAddress := widget.NewMultiLineEntry()
+	clientAddress.SetPlaceHolder("Address")
+	clientAddress.SetMinRowsVisible(2)
+	addClient := NewTerminalButton("Add Client", func() {
+		rate, err := strconv.ParseFloat(strings.TrimSpace(clientRate.Text), 64)
+		if err != nil || rate < 0 {
+			showErr(fmt.Errorf("hourly rate must be a positive number"))
+			return
+		}
+		c := &tracker.Client{
+			Name:       strings.TrimSpace(clientName.Text),
+			Currency:   strings.ToUpper(strings.TrimSpace(clientCurrency.Text)),
+			HourlyRate: rate,
+			Address:    strings.TrimSpace(clientAddress.Text),
+		}
+		if c.Name == "" || c.Currency == "" {
+			showErr(fmt.Errorf("client name and currency are required"))
+			return
+		}
+		if err := ui.storage.SaveClient(c); err != nil {
+			showErr(err)
+			return
+		}
+		clientName.SetText("")
+		clientRate.SetText("")
+		clientAddress.SetText("")
+		reload()
+	})
+	clientScroll := container.NewVScroll(clientList)
+	clientScroll.SetMinSize(fyne.NewSize(0, 140))
+	clientsTab := container.NewBorder(nil,
+		container.NewVBox(container.NewGridWithColumns(3, clientName, clientCurrency, clientRate), clientAddress, addClient),
+		nil, nil, clientScroll)
+
+	// --- Projects ---
+	projectList = widget.NewList(
+		func() int { return len(projects) },
+		func() fyne.CanvasObject {
+			label := canvas.NewText("", grey)
+			label.TextStyle = fyne.TextStyle{Monospace: true}
+			return container.NewBorder(nil, nil, nil, NewTerminalButton("Delete", nil), label)
+		},
+		func(i int, o fyne.CanvasObject) {
+			if i >= len(projects) {
+				return
+			}
+			p := projects[i]
+			clientName := "?"
+			var client tracker.Client
+			for _, c := range clients {
+				if c.ID == p.ClientID {
+					client, clientName = c, c.Name
+				}
+			}
+			row := o.(*fyne.Container)
+			label := row.Objects[0].(*canvas.Text)
+			label.Text = fmt.Sprintf("@%s | %s | %.2f %s/h", p.Name, clientName, p.Rate(client), client.Currency)
+			canvas.Refresh(label)
+			row.Objects[1].(*TerminalButton).OnTap = func() {
+				if err := ui.storage.DeleteProject(p.ID); err != nil {
+					showErr(err)
+				}
+				reload()
+			}
+		},
+	)
+	projectName := widget.NewEntry()
+	projectName.SetPlaceHolder("Project name")
+	projectRate := widget.NewEntry()
+	projectRate.SetPlaceHolder("Rate (blank = client)")
+	clientSelect = widget.NewSelect(nil, nil)
+	clientSelect.PlaceHolder = "Client"
+	addProject := NewTerminalButton("Add Project", func() {
+		client, ok := clientByName(clientSelect.Selected)
+		if !ok {
+			showErr(fmt.Errorf("select a client for the project"))
+			return
+		}
+		rate := 0.0
+		if text := strings.TrimSpace(projectRate.Text); text != "" {
+			var err error
+			if rate, err = strconv.ParseFloat(text, 64); err != nil || rate < 0 {
+				showErr(fmt.Errorf("hourly rate must be a positive number"))
+				return
+			}
+		}
+		p := &tracker.Project{
+			Name:       strings.TrimPrefix(strings.TrimSpace(projectName.Text), "@"),
+			ClientID:   client.ID,
+			HourlyRate: rate,
+		}
+		if p.Name == "" || strings.ContainsAny(p.Name, " \t") {
+			showErr(fmt.Errorf("project name must be a single word (used as @name)"))
+			return
+		}
+		if err := ui.storage.SaveProject(p); err != nil {
+			showErr(err)
+			return
+		}
+		projectName.SetText("")
+		projectRate.SetText("")
+		reload()
+	})
+	projectScroll := container.NewVScroll(projectList)
+	projectScroll.SetMinSize(fyne.NewSize(0, 140))
+	projectsTab := container.NewBorder(nil,
+		container.NewVBox(container.NewGridWithColumns(3, projectName, clientSelect, projectRate), addProject),
+		nil, nil, projectScroll)
+
+	// --- Invoice ---
+	invoiceClientSelect = widget.NewSelect(nil, nil)
+	invoiceClientSelect.PlaceHolder = "Client"
+	now := time.Now()
+	firstOfLastMonth := time.Date(now.Year(), now.Month()-1, 1, 0, 0, 0, 0, now.Location())
+	lastOfLastMonth := firstOfLastMonth.AddDate(0, 1, -1)
+	fromEntry := widget.NewDateEntry()
+	fromEntry.SetDate(&firstOfLastMonth)
+	toEntry := widget.NewDateEntry()
+	toEntry.SetDate(&lastOfLastMonth)
+	taxEntry := widget.NewEntry()
+	taxEntry.SetText(strconv.FormatFloat(ui.config.InvoiceTaxPercent, 'f', -1, 64))
+	rounding := ui.config.BillingRounding
+	roundingLabel := canvas.NewText(fmt.Sprintf("Rounding: %s to %d min per %s", rounding.Mode, rounding.IncrementMinutes, rounding.Scope), grey)
+	roundingLabel.TextStyle = fyne.TextStyle{Monospace: true}
+
+	generate := NewTerminalButton("Generate Invoice", func() {
+		client, ok := clientByName(invoiceClientSelect.Selected)
+		if !ok {
+			showErr(fmt.Errorf("select a client to invoice"))
+			return
+		}
+		if fromEntry.Date == nil || toEntry.Date == nil {
+			showErr(fmt.Errorf("pick the invoice period"))
+			return
+		}
+		tax, err := strconv.ParseFloat(strings.TrimSpace(taxEntry.Text), 64)
+		if err != nil || tax < 0 {
+			showErr(fmt.Errorf("tax must be a positive number"))
+			return
+		}
+		from := time.Date(fromEntry.Date.Year(), fromEntry.Date.Month(), fromEntry.Date.Day(), 0, 0, 0, 0, time.Local)
+		to := time.Date(toEntry.Date.Year(), toEntry.Date.Month(), toEntry.Date.Day(), 0, 0, 0, 0, time.Local).AddDate(0, 0, 1)
+		sessions, err := ui.storage.LoadSessionsInRange(from, to)
+		if err != nil {
+			showErr(err)
+			return
+		}
+		// Bill only the time inside the period; the rest belongs to neighbouring invoices
+		lines := tracker.BillLines(tracker.ClipSessions(sessions, from, to), client, projects, ui.config.BillingRounding)
+		if len(lines) == 0 {
+			showErr(fmt.Errorf("no billable sessions for %s in this period", client.Name))
+			return
+		}
+		number, err := ui.storage.NextInvoiceNumber(ui.config.InvoicePrefix, now.Year())
+		if err != nil {
+			showErr(err)
+			return
+		}
+		inv := export.Invoice{
+			Number:      number,
+			IssueDate:   now,
+			DueDate:     now.AddDate(0, 0, ui.config.InvoiceDueDays),
+			PeriodStart: from,
+			PeriodEnd:   to,
+			Issuer:      ui.config.InvoiceIssuer,
+			Client:      client,
+			Lines:       lines,
+			TaxPercent:  tax,
+		}
+		ui.config.InvoiceTaxPercent = tax
+		ui.saveConfig()
+
+		save := dialog.NewFileSave(func(uc fyne.URIWriteCloser, err error) {
+			if err != nil || uc == nil {
+				return
+			}
+			defer uc.Close()
+			if err := export.ExportInvoicePDF(inv, uc.URI().Path()); err != nil {
+				showErr(err)
+				return
+			}
+			record := storage.InvoiceRecord{Number: inv.Number, ClientID: client.ID, Issued: now, Total: inv.Total()}
+			if err := ui.storage.RecordInvoice(record); err != nil {
+				showErr(err)
+				return
+			}
+			dialog.NewInformation("Invoice Created", fmt.Sprintf("%s: %.2f %s", inv.Number, inv.Total(), client.Currency), win).Show()
+		}, win)
+		save.SetFileName(number + ".pdf")
+		save.Show()
+	})
+	invoiceTab := container.NewVBox(
+		invoiceClientSelect,
+		container.NewGridWithColumns(2, canvas.NewText("From", grey), canvas.NewText("To", grey)),
+		container.NewGridWithColumns(2, fromEntry, toEntry),
+		container.NewBorder(nil, nil, canvas.NewText("Tax %", grey), nil, taxEntry),
+		roundingLabel,
+		generate,
+	)
+
+	tabs := container.NewAppTabs(
+		container.NewTabItem("Clients", clientsTab),
+		container.NewTabItem("Projects", projectsTab),
+		container.NewTabItem("Invoice", invoiceTab),
+	)
+	reload()
+
+	d := dialog.NewCustom("Billing", "Close", tabs, win)
+	d.Resize(fyne.NewSize(620, 480))
+	d.Show()
+}
@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"fmt"
+	"katana/export"
+	"katana/tracker"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Targets of a calendar import
+const (
+	importAsSessions = "Tracked sessions"
+	importAsPlan     = "Planned blocks"
+)
+
+// showCalendarImport reads an iCalendar file and saves its timed events as
+// tracked sessions or as planned blocks
+func (ui *MainUI) showCalendarImport() {
+	win := fyne.CurrentApp().Driver().AllWindows()[0]
+	open := dialog.NewFileOpen(func(rc fyne.URIReadCloser, err error) {
+		if err != nil || rc == nil {
+			return
+		}
+		events, err := export.ReadICS(rc)
+		rc.Close()
+		if err != nil {
+			dialog.NewError(err, win).Show()
+			return
+		}
+
+		target := widget.NewRadioGroup([]string{importAsSessions, importAsPlan}, nil)
+		target.SetSelected(importAsSessions)
+		content := container.NewVBox(
+			widget.NewLabel(fmt.Sprintf("%d timed events from %s", len(events), rc.URI().Name())),
+			target,
+		)
+		dialog.NewCustomConfirm("Import Calendar", "Import", "Cancel", content, func(ok bool) {
+			if !ok {
+				return
+			}
+			// Events that don't make a valid block or session, such as busy
+			// blocks without a summary, are skipped
+			var count, skipped int
+			var err error
+			if target.Selected == importAsPlan {
+				var blocks []*tracker.PlannedBlock
+				for _, ev := range events {
+					if b := export.PlannedBlockFromEvent(ev); b.Validate() == nil {
+						blocks = append(blocks, &b)
+					}
+				}
+				skipped = len(events) - len(blocks)
+				count, err = len(blocks), ui.storage.SavePlannedBlocks(blocks)
+			} else {
+				var sessions []*tracker.Session
+				for _, ev := range events {
+					if s := export.SessionFromEvent(ev); s.Validate() == nil {
+						sessions = append(sessions, s)
+					}
+				}
+				skipped = len(events) - len(sessions)
+				count, err = ui.storage.ImportSessions(sessions)
+			}
+			if err != nil {
+				dialog.NewError(err, win).Show()
+				return
+			}
+			ui.mu.Lock()
+			ui.refreshSessions()
+			ui.mu.Unlock()
+			message := fmt.Sprintf("Imported %d of %d events as %s.", count, len(events), strings.ToLower(target.Selected))
+			if skipped > 0 {
+				message += fmt.Sprintf("\n%d events were skipped because they have no title or valid times.", skipped)
+			}
+			dialog.NewInformation("Import Calendar", message, win).Show()
+		}, win).Show()
+	}, win)
+	open.SetFilter(storage.NewExtensionFileFilter([]string{".ics"}))
+	open.Show()
+}
@@ -0,0 +1,159 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"katana/tracker"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showAddEntryDialog lets the user record a session after the fact
+func (ui *MainUI) showAddEntryDialog() {
+	win := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	activityEntry := widget.NewEntry()
+	activityEntry.SetPlaceHolder("Activity (e.g. study:math)")
+	tagEntry := widget.NewEntry()
+	tagEntry.SetPlaceHolder("Tags (optional)")
+	dateEntry := widget.NewDateEntry()
+	today := time.Now()
+	dateEntry.SetDate(&today)
+	startEntry := widget.NewEntry()
+	startEntry.SetPlaceHolder("HH:MM")
+	endEntry := widget.NewEntry()
+	endEntry.SetPlaceHolder("HH:MM")
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Activity", activityEntry),
+		widget.NewFormItem("Tags", tagEntry),
+		widget.NewFormItem("Date", dateEntry),
+		widget.NewFormItem("Start", startEntry),
+		widget.NewFormItem("End", endEntry),
+	}
+
+	form := dialog.NewForm("Add Entry", "Add", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		sess, err := buildManualSession(activityEntry.Text, tagEntry.Text, dateEntry.Date, startEntry.Text, endEntry.Text)
+		if err != nil {
+			dialog.NewError(err, win).Show()
+			return
+		}
+		ui.saveManualSession(sess)
+	}, win)
+	form.Resize(fyne.NewSize(420, 320))
+	form.Show()
+}
+
+// buildManualSession turns the Add Entry form values into a validated session.
+// An end time earlier than the start time is taken to be on the following day.
+func buildManualSession(activity, tagsText string, date *time.Time, startText, endText string) (*tracker.Session, error) {
+	activity = strings.TrimSpace(activity)
+	if activity == "" {
+		return nil, fmt.Errorf("activity name cannot be empty")
+	}
+	if len(activity) > 100 {
+		return nil, fmt.Errorf("activity name too long (max 100 characters)")
+	}
+	if date == nil {
+		return nil, fmt.Errorf("please pick a date")
+	}
+	tags, err := parseTags(tagsText)
+	if err != nil {
+		return nil, err
+	}
+	start, err := clockOnDay(*date, startText)
+	if err != nil {
+		return nil, fmt.Errorf("invalid start time: %v", err)
+	}
+	end, err := clockOnDay(*date, endText)
+	if err != nil {
+		return nil, fmt.Errorf("invalid end time: %v", err)
+	}
+	if !end.After(start) {
+		end = end.AddDate(0, 0, 1)
+	}
+	if end.After(time.Now()) {
+		return nil, fmt.Errorf("entry cannot end in the future")
+	}
+
+	sess := tracker.NewSessionAt(activity, start, end)
+	sess.Tags = append(sess.Tags, tags...)
+	if err := sess.Validate(); err != nil {
+		return nil, err
+	}
+	return sess, nil
+}
+
+// clockOnDay parses an "HH:MM" string as a local time on the given day
+func clockOnDay(day time.Time, clock string) (time.Time, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(clock))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("use HH:MM")
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), t.Hour(), t.Minute(), 0, 0, time.Local), nil
+}
+
+// saveManualSession stores sess, first asking how to handle any overlap with recorded sessions
+func (ui *MainUI) saveManualSession(sess *tracker.Session) {
+	win := fyne.CurrentApp().Driver().AllWindows()[0]
+	existing, err := ui.storage.LoadSessionsInRange(sess.StartTime, sess.EndTime)
+	if err != nil {
+		dialog.NewError(fmt.Errorf("failed to check for overlaps: %v", err), win).Show()
+		return
+	}
+
+	save := func(mode tracker.OverlapResolution) {
+		parts, err := tracker.ResolveOverlap(sess, existing, mode)
+		if err != nil {
+			dialog.NewError(err, win).Show()
+			return
+		}
+		if err := ui.storage.SaveSessions(parts); err != nil {
+			dialog.NewError(fmt.Errorf("failed to save session: %v", err), win).Show()
+			return
+		}
+		ui.mu.Lock()
+		ui.refreshSessions()
+		ui.mu.Unlock()
+	}
+
+	overlaps := tracker.FindOverlaps(sess, existing)
+	if len(overlaps) == 0 {
+		save(tracker.OverlapKeep)
+		return
+	}
+
+	terminalGreen := color.RGBA{R: 0, G: 255, B: 0, A: 255}
+	lines := []fyne.CanvasObject{
+		canvas.NewText(fmt.Sprintf("This entry overlaps %d recorded session(s):", len(overlaps)), terminalGreen),
+	}
+	for _, o := range overlaps {
+		lines = append(lines, canvas.NewText(fmt.Sprintf("  %s - %s | %s",
+			o.StartTime.Format("15:04"), o.EndTime.Format("15:04"), o.Activity), terminalGreen))
+	}
+
+	var d dialog.Dialog
+	choose := func(mode tracker.OverlapResolution) func() {
+		return func() {
+			d.Hide()
+			save(mode)
+		}
+	}
+	buttons := container.NewGridWithColumns(3,
+		NewTerminalButton("Trim", choose(tracker.OverlapTrim)),
+		NewTerminalButton("Split", choose(tracker.OverlapSplit)),
+		NewTerminalButton("Keep", choose(tracker.OverlapKeep)),
+	)
+	content := container.NewVBox(append(lines, widget.NewSeparator(), buttons)...)
+	d = dialog.NewCustom("Overlapping Entry", "Cancel", content, win)
+	d.Show()
+}
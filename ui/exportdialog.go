@@ -0,0 +1,212 @@
+package ui
+
+import (
+	"fmt"
+	"katana/config"
+	"katana/export"
+	"katana/tracker"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showExportDialog lets the user export any range of days with category,
+// tag and project filters, in a chosen format and with chosen columns
+func (ui *MainUI) showExportDialog() {
+	win := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	// Default to the month of the selected day
+	first := time.Date(ui.viewDate.Year(), ui.viewDate.Month(), 1, 0, 0, 0, 0, time.Local)
+	from, to := first, first.AddDate(0, 1, -1)
+	fromEntry := widget.NewDateEntry()
+	toEntry := widget.NewDateEntry()
+	setRange := func(f, t time.Time) {
+		from, to = tracker.DayStart(f), tracker.DayStart(t)
+		fromEntry.SetDate(&from)
+		toEntry.SetDate(&to)
+	}
+	setRange(from, to)
+	fromEntry.OnChanged = func(d *time.Time) {
+		if d != nil {
+			from = tracker.DayStart(*d)
+		}
+	}
+	toEntry.OnChanged = func(d *time.Time) {
+		if d != nil {
+			to = tracker.DayStart(*d)
+		}
+	}
+
+	day := ui.viewDate
+	presets := container.NewHBox(
+		NewTerminalButton("Day", func() { setRange(day, day) }),
+		NewTerminalButton("Week", func() {
+			monday := day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+			setRange(monday, monday.AddDate(0, 0, 6))
+		}),
+		NewTerminalButton("Month", func() {
+			m := time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.Local)
+			setRange(m, m.AddDate(0, 1, -1))
+		}),
+		NewTerminalButton("Last Month", func() {
+			m := time.Date(day.Year(), day.Month()-1, 1, 0, 0, 0, 0, time.Local)
+			setRange(m, m.AddDate(0, 1, -1))
+		}),
+		NewTerminalButton("Quarter", func() {
+			q := time.Date(day.Year(), day.Month()-(day.Month()-1)%3, 1, 0, 0, 0, 0, time.Local)
+			setRange(q, q.AddDate(0, 3, -1))
+		}),
+		NewTerminalButton("Year", func() {
+			y := time.Date(day.Year(), time.January, 1, 0, 0, 0, 0, time.Local)
+			setRange(y, y.AddDate(1, 0, -1))
+		}),
+	)
+
+	categoriesEntry := widget.NewEntry()
+	categoriesEntry.SetPlaceHolder("Categories, comma-separated (all if empty)")
+	tagsEntry := widget.NewEntry()
+	tagsEntry.SetPlaceHolder("Tags, comma-separated (all if empty)")
+	projectsEntry := widget.NewEntry()
+	projectsEntry.SetPlaceHolder("Projects, comma-separated (all if empty)")
+
+	formatNames := func() []string {
+		var formats []string
+		for _, e := range export.Exporters() {
+			formats = append(formats, e.Name())
+		}
+		return formats
+	}
+	formatSelect := widget.NewSelect(formatNames(), nil)
+	formatSelect.SetSelected("CSV")
+	// Reload picks up templates added or edited since Katana started
+	reloadBtn := NewTerminalButton("Reload", func() {
+		if err := ui.registerTemplates(); err != nil {
+			dialog.NewError(err, win).Show()
+		}
+		formatSelect.SetOptions(formatNames())
+		if export.Lookup(formatSelect.Selected) == nil {
+			formatSelect.SetSelected("CSV")
+		}
+	})
+
+	headers := make([]string, len(export.AllColumns))
+	for i, c := range export.AllColumns {
+		headers[i] = c.Header()
+	}
+	columnsCheck := widget.NewCheckGroup(headers, nil)
+	columnsCheck.Horizontal = true
+	var selected []string
+	for _, c := range export.DefaultColumns {
+		selected = append(selected, c.Header())
+	}
+	if ui.config.ExportIncludeNotes {
+		selected = append(selected, export.ColumnNotes.Header())
+	}
+	columnsCheck.SetSelected(selected)
+
+	form := widget.NewForm(
+		widget.NewFormItem("From", fromEntry),
+		widget.NewFormItem("To", toEntry),
+		widget.NewFormItem("", presets),
+		widget.NewFormItem("Categories", categoriesEntry),
+		widget.NewFormItem("Tags", tagsEntry),
+		widget.NewFormItem("Projects", projectsEntry),
+		widget.NewFormItem("Format", container.NewBorder(nil, nil, nil, reloadBtn, formatSelect)),
+		widget.NewFormItem("Columns", columnsCheck),
+	)
+
+	var d dialog.Dialog
+	exportBtn := NewTerminalButton("Export", func() {
+		if to.Before(from) {
+			dialog.NewError(fmt.Errorf("the range ends before it starts"), win).Show()
+			return
+		}
+		filter := export.Filter{
+			Categories: splitList(categoriesEntry.Text),
+			Tags:       splitList(strings.ReplaceAll(tagsEntry.Text, "#", "")),
+			Projects:   splitList(strings.ReplaceAll(projectsEntry.Text, "@", "")),
+		}
+		start, end := from, to.AddDate(0, 0, 1)
+		opts := ui.exportOptions(start, end)
+		opts.Columns = nil
+		for i, c := range export.AllColumns {
+			for _, h := range columnsCheck.Selected {
+				if h == headers[i] {
+					opts.Columns = append(opts.Columns, c)
+				}
+			}
+		}
+		if len(opts.Columns) == 0 {
+			dialog.NewError(fmt.Errorf("select at least one column"), win).Show()
+			return
+		}
+		e := export.Lookup(formatSelect.Selected)
+		if e == nil {
+			dialog.NewError(fmt.Errorf("choose a format"), win).Show()
+			return
+		}
+		report := export.Report{Start: start, End: end, Filter: filter, Options: opts}
+		ui.saveExport(e, report, fmt.Sprintf("katana-%s-%s", from.Format("20060102"), to.Format("20060102")), func() { d.Hide() })
+	})
+
+	d = dialog.NewCustom("Export", "Close", container.NewVBox(form, exportBtn), win)
+	d.Resize(fyne.NewSize(760, 460))
+	d.Show()
+}
+
+// showExportMenu pops up the registered formats below anchor and exports
+// the report built by report in the chosen one
+func (ui *MainUI) showExportMenu(anchor fyne.CanvasObject, name string, report func() export.Report) {
+	var items []*fyne.MenuItem
+	for _, e := range export.Exporters() {
+		items = append(items, fyne.NewMenuItem(e.Name(), func() {
+			ui.saveExport(e, report(), name, nil)
+		}))
+	}
+	driver := fyne.CurrentApp().Driver()
+	pos := driver.AbsolutePositionForObject(anchor).AddXY(0, anchor.Size().Height)
+	widget.ShowPopUpMenuAtPosition(fyne.NewMenu("", items...), driver.CanvasForObject(anchor), pos)
+}
+
+// saveExport asks where to save the report and exports it there with e;
+// done, if set, runs after a successful export
+func (ui *MainUI) saveExport(e export.Exporter, r export.Report, name string, done func()) {
+	win := fyne.CurrentApp().Driver().AllWindows()[0]
+	save := dialog.NewFileSave(func(uc fyne.URIWriteCloser, err error) {
+		if err != nil || uc == nil {
+			return
+		}
+		path := uc.URI().Path()
+		uc.Close()
+		if err := export.ExportRange(e, ui.storage, r, path); err != nil {
+			dialog.NewError(err, win).Show()
+			return
+		}
+		if done != nil {
+			done()
+		}
+	}, win)
+	save.SetFileName(name + "." + e.Extension())
+	save.Show()
+}
+
+// registerTemplates reloads the user's export templates. Templates that fail
+// to parse are left out and reported; the other formats stay available.
+func (ui *MainUI) registerTemplates() error {
+	return export.RegisterTemplates(config.TemplatesDir)
+}
+
+// splitList splits a comma-separated list, dropping blanks
+func splitList(text string) []string {
+	var items []string
+	for _, item := range strings.Split(text, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
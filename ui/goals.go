@@ -0,0 +1,242 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"katana/tracker"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/gen2brain/beeep"
+)
+
+// goalCheckInterval is how often goal progress is recomputed while the app runs
+const goalCheckInterval = 30 * time.Second
+
+// evaluateGoals computes progress for every configured goal as of now,
+// including the time of the sessions currently being tracked
+func (ui *MainUI) evaluateGoals(now time.Time) []tracker.GoalProgress {
+	ui.mu.Lock()
+	goals := append([]tracker.Goal(nil), ui.config.Goals...)
+	ui.mu.Unlock()
+
+	progress := make([]tracker.GoalProgress, 0, len(goals))
+	for _, g := range goals {
+		start, end := g.PeriodRange(now)
+		progress = append(progress, ui.goalProgressInRange([]tracker.Goal{g}, start, end, now)...)
+	}
+	return progress
+}
+
+// evaluateGoalsForRange returns the progress of the goals whose period is
+// exactly the days from start up to, but not including, end, e.g. the daily
+// goals for a one-day export or the monthly goals for a month
+func (ui *MainUI) evaluateGoalsForRange(start, end time.Time) []tracker.GoalProgress {
+	ui.mu.Lock()
+	var goals []tracker.Goal
+	for _, g := range ui.config.Goals {
+		if periodStart, periodEnd := g.PeriodRange(start); periodStart.Equal(start) && periodEnd.Equal(end) {
+			goals = append(goals, g)
+		}
+	}
+	ui.mu.Unlock()
+	return ui.goalProgressInRange(goals, start, end, time.Now())
+}
+
+// goalProgressInRange computes the progress of goals whose period is start
+// to end. Running sessions count only when the range includes now.
+func (ui *MainUI) goalProgressInRange(goals []tracker.Goal, start, end, now time.Time) []tracker.GoalProgress {
+	if len(goals) == 0 {
+		return nil
+	}
+	sessions, err := ui.storage.LoadSessionsInRange(start, end)
+	if err != nil {
+		log.Printf("Failed to load sessions for goals from %s: %v", start.Format("2006-01-02"), err)
+	}
+	if !now.Before(start) && now.Before(end) {
+		sessions = append(sessions, ui.engine.Snapshots()...)
+	}
+	progress := make([]tracker.GoalProgress, 0, len(goals))
+	for _, g := range goals {
+		progress = append(progress, g.Progress(sessions, start))
+	}
+	return progress
+}
+
+// createGoalsPanel returns the container that holds one progress bar per goal
+func (ui *MainUI) createGoalsPanel() fyne.CanvasObject {
+	ui.goalsBox = container.NewVBox()
+	ui.goalNotified = make(map[string]bool)
+	return ui.goalsBox
+}
+
+// updateGoals refreshes the progress bars and sends any due notifications
+func (ui *MainUI) updateGoals() {
+	terminalGreen := color.RGBA{R: 0, G: 255, B: 0, A: 255}
+	progress := ui.evaluateGoals(time.Now())
+
+	rows := make([]fyne.CanvasObject, 0, len(progress))
+	for _, p := range progress {
+		label := canvas.NewText(fmt.Sprintf("%s: %.1fh (%s)", p.Goal.Label(), p.Tracked.Hours(), p.Status()), terminalGreen)
+		label.TextStyle = fyne.TextStyle{Monospace: true}
+		if p.Exceeded() {
+			label.Color = color.RGBA{255, 0, 0, 255}
+		}
+		bar := widget.NewProgressBar()
+		bar.Max = 1
+		fraction := p.Fraction()
+		if fraction > 1 {
+			fraction = 1
+		}
+		bar.SetValue(fraction)
+		rows = append(rows, label, bar)
+	}
+	fyne.Do(func() {
+		ui.goalsBox.Objects = rows
+		ui.goalsBox.Refresh()
+	})
+
+	ui.notifyGoals(progress)
+}
+
+// notifyGoals sends each goal or budget notification at most once per period
+func (ui *MainUI) notifyGoals(progress []tracker.GoalProgress) {
+	var messages []string
+	ui.mu.Lock()
+	warnAt := float64(ui.config.BudgetWarningPercent) / 100
+	for _, p := range progress {
+		key := p.Goal.Label() + "|" + p.PeriodStart.Format("2006-01-02")
+		switch {
+		case p.Met() && !ui.goalNotified[key+"|met"]:
+			ui.goalNotified[key+"|met"] = true
+			messages = append(messages, fmt.Sprintf("Goal reached: %s", p.Goal.Label()))
+		case p.Exceeded() && !ui.goalNotified[key+"|over"]:
+			ui.goalNotified[key+"|over"] = true
+			messages = append(messages, fmt.Sprintf("Budget exceeded: %s (%.1fh)", p.Goal.Label(), p.Tracked.Hours()))
+		case p.Goal.Kind == tracker.GoalBudget && warnAt > 0 && p.Fraction() >= warnAt && !p.Exceeded() && !ui.goalNotified[key+"|warn"]:
+			ui.goalNotified[key+"|warn"] = true
+			messages = append(messages, fmt.Sprintf("Budget almost used: %s (%.0f%%)", p.Goal.Label(), p.Fraction()*100))
+		}
+	}
+	ui.mu.Unlock()
+
+	// Notifying is a D-Bus round trip, so it happens without holding ui.mu
+	for _, message := range messages {
+		beeep.Notify("Katana Goals", message, "")
+	}
+}
+
+// startGoalMonitor periodically refreshes goal progress
+func (ui *MainUI) startGoalMonitor() {
+	go func() {
+		ui.updateGoals()
+		ticker := time.NewTicker(goalCheckInterval)
+		defer ticker.Stop()
+		for {
+			<-ticker.C
+			ui.updateGoals()
+		}
+	}()
+}
+
+// showGoalsDialog lists the configured goals and budgets and lets the user add or remove them
+func (ui *MainUI) showGoalsDialog() {
+	win := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	var list *widget.List
+	list = widget.NewList(
+		func() int { return len(ui.config.Goals) },
+		func() fyne.CanvasObject {
+			label := canvas.NewText("", color.RGBA{R: 180, G: 180, B: 180, A: 255})
+			label.TextStyle = fyne.TextStyle{Monospace: true}
+			return container.NewBorder(nil, nil, nil, NewTerminalButton("Delete", nil), label)
+		},
+		func(i int, o fyne.CanvasObject) {
+			if i >= len(ui.config.Goals) {
+				return
+			}
+			row := o.(*fyne.Container)
+			label := row.Objects[0].(*canvas.Text)
+			label.Text = ui.config.Goals[i].Label()
+			canvas.Refresh(label)
+			row.Objects[1].(*TerminalButton).OnTap = func() {
+				ui.mu.Lock()
+				ui.config.Goals = append(ui.config.Goals[:i], ui.config.Goals[i+1:]...)
+				ui.mu.Unlock()
+				ui.saveConfig()
+				list.Refresh()
+				go ui.updateGoals()
+			}
+		},
+	)
+
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder("Name (optional)")
+	kindSelect := widget.NewSelect([]string{string(tracker.GoalMinimum), string(tracker.GoalBudget)}, nil)
+	kindSelect.SetSelected(string(tracker.GoalMinimum))
+	scopeSelect := widget.NewSelect([]string{string(tracker.ScopeCategory), string(tracker.ScopeTag), string(tracker.ScopeProject)}, nil)
+	scopeSelect.SetSelected(string(tracker.ScopeCategory))
+	targetEntry := widget.NewEntry()
+	targetEntry.SetPlaceHolder("e.g. study")
+	hoursEntry := widget.NewEntry()
+	hoursEntry.SetPlaceHolder("Hours")
+	periodSelect := widget.NewSelect([]string{string(tracker.PeriodDay), string(tracker.PeriodWeek), string(tracker.PeriodMonth)}, nil)
+	periodSelect.SetSelected(string(tracker.PeriodWeek))
+
+	addBtn := NewTerminalButton("Add", func() {
+		hours, err := strconv.ParseFloat(strings.TrimSpace(hoursEntry.Text), 64)
+		if err != nil {
+			dialog.NewError(fmt.Errorf("hours must be a number"), win).Show()
+			return
+		}
+		g := tracker.Goal{
+			Name:   strings.TrimSpace(nameEntry.Text),
+			Kind:   tracker.GoalKind(kindSelect.Selected),
+			Scope:  tracker.GoalScope(scopeSelect.Selected),
+			Target: strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(targetEntry.Text), "#"), "@"),
+			Hours:  hours,
+			Period: tracker.GoalPeriod(periodSelect.Selected),
+		}
+		if err := g.Validate(); err != nil {
+			dialog.NewError(err, win).Show()
+			return
+		}
+		ui.mu.Lock()
+		ui.config.Goals = append(ui.config.Goals, g)
+		ui.mu.Unlock()
+		ui.saveConfig()
+		nameEntry.SetText("")
+		targetEntry.SetText("")
+		hoursEntry.SetText("")
+		list.Refresh()
+		go ui.updateGoals()
+	})
+
+	form := container.NewVBox(
+		container.NewGridWithColumns(3, kindSelect, scopeSelect, periodSelect),
+		container.NewGridWithColumns(3, nameEntry, targetEntry, hoursEntry),
+		addBtn,
+	)
+	listScroll := container.NewVScroll(list)
+	listScroll.SetMinSize(fyne.NewSize(480, 180))
+
+	d := dialog.NewCustom("Goals & Budgets", "Close", container.NewBorder(nil, form, nil, nil, listScroll), win)
+	d.Resize(fyne.NewSize(560, 420))
+	d.Show()
+}
+
+// saveConfig writes the configuration, logging rather than interrupting on failure
+func (ui *MainUI) saveConfig() {
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+	if err := ui.config.Save(); err != nil {
+		log.Printf("Failed to save config: %v", err)
+	}
+}
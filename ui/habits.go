@@ -0,0 +1,298 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"katana/tracker"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/gen2brain/beeep"
+)
+
+// streakCalendarWeeks is how many weeks the streak calendar shows
+const streakCalendarWeeks = 20
+
+// weekdayOrder lists the days Monday first, as in the streak calendar
+var weekdayOrder = []time.Weekday{
+	time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday,
+}
+
+// evaluateHabits computes streaks for every habit as of now, including the
+// time of the sessions currently being tracked
+func (ui *MainUI) evaluateHabits(now time.Time) []tracker.HabitStatus {
+	ui.mu.Lock()
+	running := ui.engine.Snapshots()
+	habits := append([]tracker.Habit(nil), ui.config.Habits...)
+	ui.mu.Unlock()
+	if len(habits) == 0 {
+		return nil
+	}
+
+	sessions, err := ui.storage.LoadSessionsInRange(tracker.HabitHistoryStart(habits, now), now)
+	if err != nil {
+		log.Printf("Failed to load sessions for habits: %v", err)
+	}
+	sessions = append(sessions, running...)
+	status := make([]tracker.HabitStatus, len(habits))
+	for i, h := range habits {
+		status[i] = tracker.EvaluateHabit(h, sessions, now)
+	}
+	return status
+}
+
+// startHabitMonitor sends the evening reminder for habits still unmet today
+func (ui *MainUI) startHabitMonitor() {
+	ui.habitNotified = make(map[string]bool)
+	go func() {
+		ticker := time.NewTicker(goalCheckInterval)
+		defer ticker.Stop()
+		for {
+			<-ticker.C
+			ui.remindHabits(time.Now())
+		}
+	}()
+}
+
+// remindHabits notifies at most once a day per habit that is due and unmet
+// once the reminder hour has passed. Habits are only evaluated while some
+// have not been handled today, so the history is not reloaded every tick.
+func (ui *MainUI) remindHabits(now time.Time) {
+	day := now.Format("2006-01-02")
+	ui.mu.Lock()
+	hour := ui.config.HabitReminderHour
+	for key := range ui.habitNotified {
+		if !strings.HasSuffix(key, "|"+day) {
+			delete(ui.habitNotified, key)
+		}
+	}
+	pending := false
+	for _, h := range ui.config.Habits {
+		if !ui.habitNotified[h.Label()+"|"+day] {
+			pending = true
+		}
+	}
+	ui.mu.Unlock()
+	if hour <= 0 || now.Hour() < hour || !pending {
+		return
+	}
+
+	status := ui.evaluateHabits(now)
+	var messages []string
+	ui.mu.Lock()
+	for _, st := range status {
+		key := st.Habit.Label() + "|" + day
+		if ui.habitNotified[key] {
+			continue
+		}
+		// Habits already met or not due need no reminder today either
+		ui.habitNotified[key] = true
+		if !st.Today.Due || st.Today.Met {
+			continue
+		}
+		left := st.Habit.Target() - st.Today.Tracked
+		message := fmt.Sprintf("%s: %s left today", st.Habit.Label(), formatMinutesShort(left))
+		if st.Current > 0 {
+			message += fmt.Sprintf(" to keep your %d-day streak", st.Current)
+		}
+		messages = append(messages, message)
+	}
+	ui.mu.Unlock()
+
+	for _, message := range messages {
+		beeep.Notify("Katana Habits", message, "")
+	}
+}
+
+// habitSummary describes a habit's streaks on one line
+func habitSummary(st tracker.HabitStatus) string {
+	today := "not due today"
+	if st.Today.Due {
+		today = fmt.Sprintf("today %s/%dm", formatMinutesShort(st.Today.Tracked), st.Habit.Minutes)
+		if st.Today.Met {
+			today += " ✓"
+		}
+	}
+	return fmt.Sprintf("%s | streak %d | best %d | missed %d | %s",
+		st.Habit.Label(), st.Current, st.Longest, st.Missed, today)
+}
+
+// showHabitsDialog lists the habits with their streaks and lets the user add or remove them
+func (ui *MainUI) showHabitsDialog() {
+	win := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	status := ui.evaluateHabits(time.Now())
+	var list *widget.List
+	reload := func() {
+		status = ui.evaluateHabits(time.Now())
+		list.Refresh()
+	}
+	list = widget.NewList(
+		func() int { return len(status) },
+		func() fyne.CanvasObject {
+			label := canvas.NewText("", color.RGBA{R: 180, G: 180, B: 180, A: 255})
+			label.TextStyle = fyne.TextStyle{Monospace: true}
+			buttons := container.NewHBox(NewTerminalButton("Calendar", nil), NewTerminalButton("Delete", nil))
+			return container.NewBorder(nil, nil, nil, buttons, label)
+		},
+		func(i int, o fyne.CanvasObject) {
+			if i >= len(status) {
+				return
+			}
+			st := status[i]
+			row := o.(*fyne.Container)
+			label := row.Objects[0].(*canvas.Text)
+			label.Text = habitSummary(st)
+			canvas.Refresh(label)
+			buttons := row.Objects[1].(*fyne.Container)
+			buttons.Objects[0].(*TerminalButton).OnTap = func() {
+				ui.showStreakCalendar(st)
+			}
+			buttons.Objects[1].(*TerminalButton).OnTap = func() {
+				ui.mu.Lock()
+				if i < len(ui.config.Habits) {
+					ui.config.Habits = append(ui.config.Habits[:i], ui.config.Habits[i+1:]...)
+				}
+				ui.mu.Unlock()
+				ui.saveConfig()
+				reload()
+			}
+		},
+	)
+
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder("Name (optional)")
+	categoryEntry := widget.NewEntry()
+	categoryEntry.SetPlaceHolder("Category, e.g. reading")
+	minutesEntry := widget.NewEntry()
+	minutesEntry.SetPlaceHolder("Minutes per day")
+	dayNames := make([]string, len(weekdayOrder))
+	for i, d := range weekdayOrder {
+		dayNames[i] = d.String()[:3]
+	}
+	daysCheck := widget.NewCheckGroup(dayNames, nil)
+	daysCheck.Horizontal = true
+
+	addBtn := NewTerminalButton("Add", func() {
+		minutes, err := strconv.Atoi(strings.TrimSpace(minutesEntry.Text))
+		if err != nil {
+			dialog.NewError(fmt.Errorf("minutes must be a whole number"), win).Show()
+			return
+		}
+		h := tracker.Habit{
+			Name:     strings.TrimSpace(nameEntry.Text),
+			Category: strings.TrimSpace(categoryEntry.Text),
+			Minutes:  minutes,
+			Created:  tracker.DayStart(time.Now()),
+		}
+		for i, name := range dayNames {
+			for _, selected := range daysCheck.Selected {
+				if selected == name {
+					h.Days = append(h.Days, weekdayOrder[i])
+				}
+			}
+		}
+		if err := h.Validate(); err != nil {
+			dialog.NewError(err, win).Show()
+			return
+		}
+		ui.mu.Lock()
+		ui.config.Habits = append(ui.config.Habits, h)
+		ui.mu.Unlock()
+		ui.saveConfig()
+		nameEntry.SetText("")
+		categoryEntry.SetText("")
+		minutesEntry.SetText("")
+		daysCheck.SetSelected(nil)
+		reload()
+	})
+
+	hint := canvas.NewText("No days selected means every day", color.RGBA{R: 180, G: 180, B: 180, A: 255})
+	hint.TextStyle = fyne.TextStyle{Italic: true, Monospace: true}
+	form := container.NewVBox(
+		container.NewGridWithColumns(3, nameEntry, categoryEntry, minutesEntry),
+		daysCheck,
+		hint,
+		addBtn,
+	)
+	listScroll := container.NewVScroll(list)
+	listScroll.SetMinSize(fyne.NewSize(640, 180))
+
+	d := dialog.NewCustom("Habits", "Close", container.NewBorder(nil, form, nil, nil, listScroll), win)
+	d.Resize(fyne.NewSize(760, 440))
+	d.Show()
+}
+
+// showStreakCalendar shows the last weeks of a habit, one column per week
+// and one row per weekday: green when met, red when missed, yellow for
+// today while still open
+func (ui *MainUI) showStreakCalendar(st tracker.HabitStatus) {
+	win := fyne.CurrentApp().Driver().AllWindows()[0]
+	terminalGreen := color.RGBA{R: 0, G: 255, B: 0, A: 255}
+	grey := color.RGBA{R: 180, G: 180, B: 180, A: 255}
+
+	days := make(map[time.Time]tracker.HabitDay)
+	for _, d := range st.Days {
+		days[d.Date] = d
+	}
+	today := st.Today.Date
+	// Start on the Monday streakCalendarWeeks-1 weeks before this week
+	offset := (int(today.Weekday()) + 6) % 7
+	first := today.AddDate(0, 0, -offset-7*(streakCalendarWeeks-1))
+
+	cells := []fyne.CanvasObject{layoutSpacer()}
+	for w := 0; w < streakCalendarWeeks; w++ {
+		label := ""
+		if monday := first.AddDate(0, 0, 7*w); monday.Day() <= 7 {
+			label = monday.Format("Jan")
+		}
+		month := canvas.NewText(label, grey)
+		month.TextSize = 10
+		cells = append(cells, month)
+	}
+	for r, weekday := range weekdayOrder {
+		name := canvas.NewText(weekday.String()[:3], grey)
+		name.TextStyle = fyne.TextStyle{Monospace: true}
+		name.TextSize = 10
+		cells = append(cells, name)
+		for w := 0; w < streakCalendarWeeks; w++ {
+			date := first.AddDate(0, 0, 7*w+r)
+			fill, stroke := color.Color(color.Black), color.Color(color.RGBA{R: 60, G: 60, B: 60, A: 255})
+			if d, ok := days[date]; ok && d.Due {
+				switch {
+				case d.Met:
+					fill, stroke = terminalGreen, terminalGreen
+				case date.Equal(today):
+					stroke = color.RGBA{R: 255, G: 255, B: 0, A: 255}
+				default:
+					fill, stroke = color.RGBA{R: 255, G: 0, B: 0, A: 255}, color.RGBA{R: 255, G: 0, B: 0, A: 255}
+				}
+			}
+			cell := canvas.NewRectangle(fill)
+			cell.StrokeColor = stroke
+			cell.StrokeWidth = 1
+			cell.SetMinSize(fyne.NewSize(14, 14))
+			cells = append(cells, cell)
+		}
+	}
+
+	summary := canvas.NewText(fmt.Sprintf("Current streak %d | longest %d | missed %d", st.Current, st.Longest, st.Missed), terminalGreen)
+	summary.TextStyle = fyne.TextStyle{Monospace: true}
+	content := container.NewVBox(
+		container.NewGridWithColumns(streakCalendarWeeks+1, cells...),
+		summary,
+	)
+	dialog.NewCustom(st.Habit.Label(), "Close", content, win).Show()
+}
+
+// layoutSpacer is an empty cell for grid layouts
+func layoutSpacer() fyne.CanvasObject {
+	return canvas.NewRectangle(color.Transparent)
+}
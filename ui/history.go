@@ -0,0 +1,297 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"katana/tracker"
+	"sort"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showSessionHistory opens a day-by-day list of recorded sessions with split,
+// merge and edit actions. Typing a search query lists matching sessions from
+// all days instead.
+func (ui *MainUI) showSessionHistory() {
+	win := fyne.CurrentApp().Driver().AllWindows()[0]
+	terminalGreen := color.RGBA{R: 0, G: 255, B: 0, A: 255}
+
+	day := time.Now()
+	query := ""
+	var sessions []*tracker.Session
+	selected := make(map[*tracker.Session]bool)
+
+	list := widget.NewList(
+		func() int { return len(sessions) },
+		func() fyne.CanvasObject {
+			label := canvas.NewText("", color.RGBA{R: 180, G: 180, B: 180, A: 255})
+			label.TextStyle = fyne.TextStyle{Monospace: true}
+			return container.NewHBox(widget.NewCheck("", nil), label)
+		},
+		func(i int, o fyne.CanvasObject) {
+			if i >= len(sessions) {
+				return
+			}
+			s := sessions[i]
+			row := o.(*fyne.Container)
+			check := row.Objects[0].(*widget.Check)
+			check.OnChanged = nil
+			check.SetChecked(selected[s])
+			check.OnChanged = func(on bool) {
+				if on {
+					selected[s] = true
+				} else {
+					delete(selected, s)
+				}
+			}
+			label := row.Objects[1].(*canvas.Text)
+			label.Text = fmt.Sprintf("%s - %s | %s [%s] %s", s.StartTime.Format("15:04"), s.EndTime.Format("15:04"),
+				s.Activity, strings.Join(s.Tags, ", "), s.GetFormattedDuration())
+			if query != "" {
+				label.Text = s.StartTime.Format("2006-01-02 ") + label.Text
+			}
+			if s.Notes != "" {
+				label.Text += " | " + notesPreview(s.Notes)
+			}
+			canvas.Refresh(label)
+		},
+	)
+
+	reload := func() {
+		if query != "" {
+			sessions, _ = ui.storage.SearchSessions(query)
+		} else {
+			// Whole sessions, so one crossing midnight is edited as a single session
+			dayStart := tracker.DayStart(day)
+			sessions, _ = ui.storage.LoadSessionsInRange(dayStart, dayStart.AddDate(0, 0, 1))
+			sort.Slice(sessions, func(i, j int) bool {
+				return sessions[i].StartTime.Before(sessions[j].StartTime)
+			})
+		}
+		selected = make(map[*tracker.Session]bool)
+		list.Refresh()
+	}
+
+	// commit persists an edit, then refreshes both this dialog and the main tab
+	commit := func(removed, added, updated []*tracker.Session) {
+		if err := ui.storage.ReplaceSessions(removed, added, updated); err != nil {
+			dialog.NewError(fmt.Errorf("failed to save changes: %v", err), win).Show()
+			return
+		}
+		ui.mu.Lock()
+		ui.refreshSessions()
+		ui.mu.Unlock()
+		reload()
+	}
+
+	chosen := func() []*tracker.Session {
+		var out []*tracker.Session
+		for _, s := range sessions {
+			if selected[s] {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+
+	dateEntry := widget.NewDateEntry()
+	dateEntry.SetDate(&day)
+	dateEntry.OnChanged = func(t *time.Time) {
+		if t != nil {
+			day = *t
+			reload()
+		}
+	}
+
+	searchEntry := widget.NewEntry()
+	searchEntry.SetPlaceHolder("Search activities, notes and tags")
+	searchEntry.OnChanged = func(text string) {
+		query = strings.TrimSpace(text)
+		if query == "" {
+			dateEntry.Enable()
+		} else {
+			dateEntry.Disable()
+		}
+		reload()
+	}
+
+	splitBtn := NewTerminalButton("Split", func() {
+		picked := chosen()
+		if len(picked) != 1 {
+			dialog.NewError(fmt.Errorf("select exactly one session to split"), win).Show()
+			return
+		}
+		ui.showSplitDialog(picked[0], func(first, second *tracker.Session) {
+			commit([]*tracker.Session{picked[0]}, []*tracker.Session{first, second}, nil)
+		})
+	})
+	mergeBtn := NewTerminalButton("Merge", func() {
+		picked := chosen()
+		if err := tracker.CheckAdjacent(picked, sessions); err != nil {
+			dialog.NewError(err, win).Show()
+			return
+		}
+		merged, err := tracker.Merge(picked)
+		if err != nil {
+			dialog.NewError(err, win).Show()
+			return
+		}
+		commit(picked, []*tracker.Session{merged}, nil)
+	})
+	editBtn := NewTerminalButton("Edit", func() {
+		picked := chosen()
+		if len(picked) != 1 {
+			dialog.NewError(fmt.Errorf("select exactly one session to edit"), win).Show()
+			return
+		}
+		ui.showEditSessionDialog(picked[0], func(edited *tracker.Session) {
+			commit(nil, nil, []*tracker.Session{edited})
+		})
+	})
+
+	reload()
+
+	listScroll := container.NewVScroll(list)
+	listScroll.SetMinSize(fyne.NewSize(520, 260))
+	border := canvas.NewRectangle(color.Transparent)
+	border.StrokeColor = terminalGreen
+	border.StrokeWidth = 1
+
+	content := container.NewBorder(
+		container.NewVBox(dateEntry, searchEntry, widget.NewSeparator()),
+		container.NewGridWithColumns(3, splitBtn, mergeBtn, editBtn),
+		nil, nil,
+		container.NewStack(border, listScroll),
+	)
+	d := dialog.NewCustom("Session History", "Close", content, win)
+	d.Resize(fyne.NewSize(600, 460))
+	d.Show()
+}
+
+// showSplitDialog asks where to split s and what each half should be called
+func (ui *MainUI) showSplitDialog(s *tracker.Session, onSplit func(first, second *tracker.Session)) {
+	win := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	mid := s.StartTime.Add(s.EndTime.Sub(s.StartTime) / 2)
+	atEntry := widget.NewEntry()
+	atEntry.SetText(mid.Format("15:04"))
+	firstActivity := widget.NewEntry()
+	firstActivity.SetText(s.Activity)
+	firstTags := widget.NewEntry()
+	firstTags.SetText(strings.Join(s.Tags, ", "))
+	secondActivity := widget.NewEntry()
+	secondActivity.SetText(s.Activity)
+	secondTags := widget.NewEntry()
+	secondTags.SetText(strings.Join(s.Tags, ", "))
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Split at", atEntry),
+		widget.NewFormItem("First activity", firstActivity),
+		widget.NewFormItem("First tags", firstTags),
+		widget.NewFormItem("Second activity", secondActivity),
+		widget.NewFormItem("Second tags", secondTags),
+	}
+	form := dialog.NewForm("Split Session", "Split", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		at, err := clockOnDay(s.StartTime, atEntry.Text)
+		if err != nil {
+			dialog.NewError(fmt.Errorf("invalid split time: %v", err), win).Show()
+			return
+		}
+		if !at.After(s.StartTime) {
+			// The session crosses midnight and the split is on the next day
+			at = at.AddDate(0, 0, 1)
+		}
+		first, second, err := tracker.Split(s, at)
+		if err != nil {
+			dialog.NewError(err, win).Show()
+			return
+		}
+		if err := applySessionText(first, firstActivity.Text, firstTags.Text); err != nil {
+			dialog.NewError(err, win).Show()
+			return
+		}
+		if err := applySessionText(second, secondActivity.Text, secondTags.Text); err != nil {
+			dialog.NewError(err, win).Show()
+			return
+		}
+		onSplit(first, second)
+	}, win)
+	form.Resize(fyne.NewSize(420, 340))
+	form.Show()
+}
+
+// showEditSessionDialog edits the activity, tags, project, billable flag and notes of a recorded session
+func (ui *MainUI) showEditSessionDialog(s *tracker.Session, onSave func(edited *tracker.Session)) {
+	win := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	activityEntry := widget.NewEntry()
+	activityEntry.SetText(s.Activity)
+	tagEntry := widget.NewEntry()
+	tagEntry.SetText(strings.Join(s.Tags, ", "))
+	projectEntry := widget.NewEntry()
+	projectEntry.SetPlaceHolder("Project (optional)")
+	projectEntry.SetText(s.Project)
+	billableCheck := widget.NewCheck("Billable", nil)
+	billableCheck.SetChecked(s.Billable)
+	notesEntry := widget.NewMultiLineEntry()
+	notesEntry.Wrapping = fyne.TextWrapWord
+	notesEntry.SetMinRowsVisible(4)
+	notesEntry.SetText(s.Notes)
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Activity", activityEntry),
+		widget.NewFormItem("Tags", tagEntry),
+		widget.NewFormItem("Project", projectEntry),
+		widget.NewFormItem("", billableCheck),
+		widget.NewFormItem("Notes", notesEntry),
+	}
+	form := dialog.NewForm("Edit Session", "Save", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		edited := *s
+		if err := applySessionText(&edited, activityEntry.Text, tagEntry.Text); err != nil {
+			dialog.NewError(err, win).Show()
+			return
+		}
+		edited.Project = strings.TrimPrefix(strings.TrimSpace(projectEntry.Text), "@")
+		edited.Billable = billableCheck.Checked
+		edited.Notes = strings.TrimSpace(notesEntry.Text)
+		onSave(&edited)
+	}, win)
+	form.Resize(fyne.NewSize(460, 420))
+	form.Show()
+}
+
+// notesPreview returns the first line of notes, shortened for a list row
+func notesPreview(notes string) string {
+	line := strings.TrimSpace(strings.SplitN(strings.TrimSpace(notes), "\n", 2)[0])
+	if runes := []rune(line); len(runes) > 40 {
+		line = string(runes[:40]) + "…"
+	}
+	return line
+}
+
+// applySessionText validates and sets the activity and tags of s
+func applySessionText(s *tracker.Session, activity, tagsText string) error {
+	activity = strings.TrimSpace(activity)
+	if len(activity) > 100 {
+		return fmt.Errorf("activity name too long (max 100 characters)")
+	}
+	tags, err := parseTags(tagsText)
+	if err != nil {
+		return err
+	}
+	s.Activity = activity
+	s.Tags = tags
+	return s.Validate()
+}
@@ -0,0 +1,135 @@
+package ui
+
+import (
+	"fmt"
+	"katana/idle"
+	"katana/tracker"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/gen2brain/beeep"
+)
+
+// idlePollInterval is how often the idle source is queried
+const idlePollInterval = 5 * time.Second
+
+// startIdleMonitor pauses the running timers once the user has been idle for
+// the configured threshold, and asks what to do with that time when they return
+func (ui *MainUI) startIdleMonitor() {
+	if ui.idleSource == nil || ui.config == nil || ui.config.IdleThresholdMinutes <= 0 {
+		return
+	}
+	monitor := &idle.Monitor{
+		Source:    ui.idleSource,
+		Threshold: time.Duration(ui.config.IdleThresholdMinutes) * time.Minute,
+	}
+
+	go func() {
+		ticker := time.NewTicker(idlePollInterval)
+		defer ticker.Stop()
+		for {
+			<-ticker.C
+			var active, paused []*tracker.Session
+			for _, st := range ui.engine.Status() {
+				if st.Paused {
+					paused = append(paused, st.Session)
+				} else {
+					active = append(active, st.Session)
+				}
+			}
+			ui.mu.Lock()
+			switch action, idleFor := monitor.Check(len(active) > 0, len(paused) > 0 && !ui.idlePromptOpen); action {
+			case idle.Pause:
+				// Pause from the moment input stopped, not from when we noticed
+				for _, sess := range active {
+					ui.engine.Pause(sess, ui.engine.Now().Add(-idleFor))
+				}
+				beeep.Notify("Katana Time Tracker", "You seem to be away - timers paused", "")
+			case idle.Resolve:
+				ui.idlePromptOpen = true
+				fyne.Do(func() {
+					ui.showIdleResolution(paused)
+				})
+			}
+			ui.mu.Unlock()
+		}
+	}()
+}
+
+// showIdleResolution asks whether the idle period of the paused sessions
+// should be kept, discarded or recorded as a different activity
+func (ui *MainUI) showIdleResolution(sessions []*tracker.Session) {
+	win := fyne.CurrentApp().Driver().AllWindows()[0]
+	idleStart := ui.engine.Now()
+	for _, st := range ui.engine.Status() {
+		for _, sess := range sessions {
+			if st.Session == sess && st.Paused && st.PausedAt.Before(idleStart) {
+				idleStart = st.PausedAt
+			}
+		}
+	}
+	idleEnd := ui.engine.Now()
+
+	var d dialog.Dialog
+	// resolve applies the choice to the sessions that are still running and paused
+	resolve := func(keep bool, reassigned *tracker.Session) {
+		d.Hide()
+		ui.mu.Lock()
+		defer ui.mu.Unlock()
+		ui.idlePromptOpen = false
+		if reassigned != nil {
+			if err := ui.storage.SaveSession(reassigned); err != nil {
+				dialog.NewError(fmt.Errorf("failed to save session: %v", err), win).Show()
+				return
+			}
+			ui.refreshSessions()
+		}
+		// The engine ignores sessions that were stopped meanwhile
+		for _, sess := range sessions {
+			ui.engine.Resume(sess, idleEnd, keep)
+		}
+	}
+
+	keepBtn := NewTerminalButton("Keep", func() { resolve(true, nil) })
+	discardBtn := NewTerminalButton("Discard", func() { resolve(false, nil) })
+	reassignBtn := NewTerminalButton("Reassign", func() {
+		activityEntry := widget.NewEntry()
+		activityEntry.SetPlaceHolder("What were you doing?")
+		tagEntry := widget.NewEntry()
+		tagEntry.SetPlaceHolder("Tags (optional)")
+		items := []*widget.FormItem{
+			widget.NewFormItem("Activity", activityEntry),
+			widget.NewFormItem("Tags", tagEntry),
+		}
+		dialog.NewForm("Reassign Idle Time", "Save", "Cancel", items, func(ok bool) {
+			if !ok {
+				return
+			}
+			tags, err := parseTags(tagEntry.Text)
+			if err != nil {
+				dialog.NewError(err, win).Show()
+				return
+			}
+			other := tracker.NewSessionAt(strings.TrimSpace(activityEntry.Text), idleStart, idleEnd)
+			other.Tags = append(other.Tags, tags...)
+			if err := other.Validate(); err != nil {
+				dialog.NewError(err, win).Show()
+				return
+			}
+			resolve(false, other)
+		}, win).Show()
+	})
+
+	message := widget.NewLabel(fmt.Sprintf("You were idle from %s to %s (%s).\nWhat should happen to this time?",
+		idleStart.Format("15:04"), idleEnd.Format("15:04"), formatMinutesShort(idleEnd.Sub(idleStart))))
+	content := container.NewVBox(
+		message,
+		container.NewGridWithColumns(3, keepBtn, discardBtn, reassignBtn),
+	)
+	d = dialog.NewCustomWithoutButtons("Welcome Back", content, win)
+	d.Show()
+}
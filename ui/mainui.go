@@ -3,13 +3,14 @@ package ui
 import (
 	"fmt"
 	"io"
+	"katana/config"
 	"katana/export"
+	"katana/idle"
 	"katana/power"
 	"katana/sound"
 	"katana/storage"
 	"katana/tracker"
 	"log"
-	"os"
 	"strconv"
 	"strings"
 	"sync"
@@ -34,30 +35,51 @@ func init() {
 type MainUI struct {
 	Container fyne.CanvasObject
 	// Time Tracker tab components
-	activityEntry                 *widget.Entry
+	activityEntry                 *widget.SelectEntry // Suggests recent activities
 	tagEntry                      *widget.Entry // New: for entering tags
-	startStopBtn                  *TerminalButton
-	isTracking                    bool
-	currentSession                *tracker.Session
+	billableCheck                 *widget.Check
+	notesEntry                    *widget.Entry // Stays editable while tracking
+	engine                        *tracker.Engine // Owns the running sessions
+	timers                        []*runningTimer // One row per running session, in start order
+	timersBox                     *fyne.Container
+	estimates                     map[*tracker.Session]time.Duration // Template estimates of sessions about to start
+	pomodoroActive                bool                               // A Pomodoro run is in progress
+	favoritesBox                  *fyne.Container
+	recentActivities              []string
+	startCountdown                func(d time.Duration) // Starts the Countdown tab, set when the tab is created
 	storage                       *storage.Storage
 	soundPlayer                   *sound.Player       // Sound player for alarm sounds
 	powerManager                  *power.PowerManager // Power manager for sleep prevention
+	config                        *config.Config
+	idleSource                    idle.Source // nil when idle detection is unavailable
+	idlePromptOpen                bool        // True while the idle resolution dialog is shown
 	mu                            sync.Mutex
 	timerLabel                    *widget.Label
 	activityList                  *widget.List
 	sessionsToday                 []*tracker.Session
 	allSessionsToday              []*tracker.Session
 	updateActivityListPlaceholder func()
+	updateAnalytics               func()
 	originalTabLabels             []string
 	viewerContents                []fyne.CanvasObject
+	viewerStale                   []bool // Viewers whose sessions changed while hidden, rebuilt when shown
+	yearView                      *yearViewer
+	reportView                    *reportViewer
+	viewDate                      time.Time // Day shown by the viewers, the activity list and the exports
+	viewDateEntry                 *widget.DateEntry
+	timeline                      *dayTimeline
 	contentContainer              *fyne.Container
 	tabBar                        *TerminalTabBar
-	notificationSent              bool // Track if 2-hour notification has been sent
+	goalsBox                      *fyne.Container
+	goalNotified                  map[string]bool // Goal notifications already sent, by goal and period
+	habitNotified                 map[string]bool // Habits handled by today's reminder, by habit and day
+	rules                         *tracker.RuleSet // Auto-categorization rules, nil if the rules file is invalid
 
 	// Main application tabs
 	mainTabContainer *CustomMainTabContainer
 	timeTrackerTab   *container.TabItem
 	stopwatchTab     *container.TabItem
+	pomodoroTab      *container.TabItem
 	countdownTab     *container.TabItem
 	alarmTab         *container.TabItem
 }
@@ -391,7 +413,7 @@ func (te *TerminalEntryWidget) CreateRenderer() fyne.WidgetRenderer {
 }
 
 // NewMainUI returns the main UI object and error for error handling
-func NewMainUI() (*MainUI, error) {
+func NewMainUI(cfg *config.Config) (*MainUI, error) {
 	fyne.CurrentApp().Settings().SetTheme(&terminalTheme{})
 	st, err := storage.NewStorage()
 	if err != nil {
@@ -413,18 +435,31 @@ func NewMainUI() (*MainUI, error) {
 	// Initialize power manager
 	powerManager := power.NewPowerManager()
 
+	// Idle detection is optional; without a source the timer simply keeps running
+	idleSource, err := idle.NewSystemSource()
+	if err != nil {
+		log.Printf("Idle detection disabled: %v", err)
+	}
+
 	sessionsToday, _ := st.LoadSessionsForDay(time.Now())
 	ui := &MainUI{
-		isTracking:        false,
 		storage:           st,
+		engine:            tracker.NewEngine(st, time.Duration(cfg.NotificationThresholdHours*float64(time.Hour))),
 		soundPlayer:       soundPlayer,
 		powerManager:      powerManager,
+		config:            cfg,
+		idleSource:        idleSource,
 		timerLabel:        widget.NewLabel("00:00:00"),
 		sessionsToday:     sessionsToday,
 		allSessionsToday:  sessionsToday, // Store unfiltered sessions
-		originalTabLabels: []string{"Daily", "Weekly", "Monthly"},
+		originalTabLabels: []string{"Daily", "Weekly", "Monthly", "Yearly", "Plan", "Reports"},
+		estimates:         make(map[*tracker.Session]time.Duration),
+		viewDate:          tracker.DayStart(time.Now()),
 	}
 
+	ui.engine.SetSplitAtMidnight(cfg.SplitAtMidnight)
+	ui.loadRules()
+
 	// Create the main application title
 	terminalGreen := color.RGBA{R: 0, G: 255, B: 0, A: 255}
 	appTitle := canvas.NewText("Katana Multi-Timer", terminalGreen)
@@ -434,6 +469,7 @@ func NewMainUI() (*MainUI, error) {
 	// Create main tabs
 	ui.timeTrackerTab = ui.createTimeTrackerTab()
 	ui.stopwatchTab = ui.createStopwatchTab()
+	ui.pomodoroTab = ui.createPomodoroTab()
 	ui.countdownTab = ui.createCountdownTab()
 	ui.alarmTab = ui.createAlarmTab()
 
@@ -441,9 +477,11 @@ func NewMainUI() (*MainUI, error) {
 	ui.mainTabContainer = NewCustomMainTabContainer(
 		ui.timeTrackerTab,
 		ui.stopwatchTab,
+		ui.pomodoroTab,
 		ui.countdownTab,
 		ui.alarmTab,
 	)
+	ui.registerTemplateShortcuts()
 
 	// Main application layout
 	ui.Container = container.NewVBox(
@@ -479,11 +517,7 @@ func makeHourGrid(sessions []*tracker.Session, terminalGreen color.Color) fyne.C
 	// Build a map of hours with activity
 	activeHours := make(map[int]bool)
 	for _, s := range sessions {
-		startHour := s.StartTime.Hour()
-		endHour := s.EndTime.Hour()
-		for h := startHour; h <= endHour; h++ {
-			activeHours[h] = true
-		}
+		markHours(activeHours, s.StartTime, s.EndTime)
 	}
 	for i := 0; i < 24; i++ {
 		var rectColor, textColor color.Color
@@ -505,18 +539,37 @@ func makeHourGrid(sessions []*tracker.Session, terminalGreen color.Color) fyne.C
 	return container.NewGridWithColumns(8, boxes...)
 }
 
-// Weekly grid with day labels and total time tracked, responsive
-func makeWeekGrid(storage *storage.Storage, terminalGreen color.Color) fyne.CanvasObject {
+// markHours marks the hours of the day touched by [start, end); the end is
+// exclusive, so a span ending at 10:00 does not mark the 10:00 box
+func markHours(hours map[int]bool, start, end time.Time) {
+	hour := time.Date(start.Year(), start.Month(), start.Day(), start.Hour(), 0, 0, 0, start.Location())
+	for ; hour.Before(end); hour = hour.Add(time.Hour) {
+		hours[hour.Hour()] = true
+	}
+}
+
+// Daily viewer: minute timeline, hour grid and the number of pomodoros completed that day
+func (ui *MainUI) makeDailyViewer(day time.Time, sessions []*tracker.Session, terminalGreen color.Color) fyne.CanvasObject {
+	ui.timeline.SetSessions(day, sessions)
+	grid := makeHourGrid(sessions, terminalGreen)
+	viewer := container.NewVBox(ui.timeline.Controls(), ui.timeline, grid)
+	if count := tracker.CountPomodoros(sessions); count > 0 {
+		label := canvas.NewText(fmt.Sprintf("🍅 %d pomodoros completed", count), terminalGreen)
+		label.TextStyle = fyne.TextStyle{Monospace: true}
+		viewer.Add(container.NewCenter(label))
+	}
+	return viewer
+}
+
+// Weekly grid of the seven days ending on the given day, with day labels and total time tracked, responsive
+func makeWeekGrid(storage *storage.Storage, policy tracker.OverlapPolicy, day time.Time, terminalGreen color.Color) fyne.CanvasObject {
 	days := 7
 	boxes := make([]fyne.CanvasObject, days)
-	today := time.Now()
+	today := day
 	for i := 0; i < days; i++ {
 		date := today.AddDate(0, 0, -i)
 		sessions, _ := storage.LoadSessionsForDay(date)
-		total := 0.0
-		for _, s := range sessions {
-			total += s.Duration.Hours()
-		}
+		total := tracker.TotalTracked(sessions, policy).Hours()
 		var rectColor, textColor color.Color
 		if total > 0 {
 			rectColor = terminalGreen
@@ -539,17 +592,21 @@ func makeWeekGrid(storage *storage.Storage, terminalGreen color.Color) fyne.Canv
 		}(), textColor)
 		timeLabel.TextStyle = fyne.TextStyle{Bold: true, Monospace: true}
 		timeLabel.Alignment = fyne.TextAlignCenter
+		pomoLabel := canvas.NewText(pomodoroBadge(sessions), textColor)
+		pomoLabel.TextStyle = fyne.TextStyle{Monospace: true}
+		pomoLabel.Alignment = fyne.TextAlignCenter
 		boxes[days-1-i] = container.NewMax(rect, container.NewVBox(
 			container.NewCenter(dayLabel),
 			container.NewCenter(timeLabel),
+			container.NewCenter(pomoLabel),
 		))
 	}
 	return container.NewGridWithColumns(7, boxes...)
 }
 
-// Monthly grid with day-of-month labels, dynamic days, responsive
-func makeMonthGrid(storage *storage.Storage, terminalGreen color.Color) fyne.CanvasObject {
-	today := time.Now()
+// Monthly grid of the given day's month with day-of-month labels, dynamic days, responsive
+func makeMonthGrid(storage *storage.Storage, day time.Time, terminalGreen color.Color) fyne.CanvasObject {
+	today := day
 	firstOfMonth := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, today.Location())
 	nextMonth := firstOfMonth.AddDate(0, 1, 0)
 	days := int(nextMonth.Sub(firstOfMonth).Hours() / 24)
@@ -571,119 +628,146 @@ func makeMonthGrid(storage *storage.Storage, terminalGreen color.Color) fyne.Can
 		label := canvas.NewText(fmt.Sprintf("%d", date.Day()), textColor)
 		label.TextStyle = fyne.TextStyle{Monospace: true}
 		label.Alignment = fyne.TextAlignCenter
-		boxes[i] = container.NewMax(rect, container.NewCenter(label))
+		pomoLabel := canvas.NewText(pomodoroBadge(sessions), textColor)
+		pomoLabel.TextStyle = fyne.TextStyle{Monospace: true}
+		pomoLabel.TextSize = 10
+		pomoLabel.Alignment = fyne.TextAlignCenter
+		boxes[i] = container.NewMax(rect, container.NewCenter(container.NewVBox(label, pomoLabel)))
 	}
 	return container.NewGridWithColumns(8, boxes...)
 }
 
-func (ui *MainUI) toggleTracking() {
+// pomodoroBadge returns a short pomodoro count for a grid cell, or "" if there were none
+func pomodoroBadge(sessions []*tracker.Session) string {
+	if count := tracker.CountPomodoros(sessions); count > 0 {
+		return fmt.Sprintf("🍅%d", count)
+	}
+	return ""
+}
+
+// startTracking starts a new timer from the activity entries. Other timers
+// keep running, so several sessions can be tracked at once.
+func (ui *MainUI) startTracking() {
 	ui.mu.Lock()
 	defer ui.mu.Unlock()
-	if !ui.isTracking {
-		activity := ui.activityEntry.Text
-		tagsText := ui.tagEntry.Text
-		if activity == "" {
-			// Show error dialog for empty activity
-			dialog.NewError(
-				fmt.Errorf("activity name cannot be empty"),
-				fyne.CurrentApp().Driver().AllWindows()[0],
-			).Show()
-			return
-		}
-		// Validate activity length
-		if len(strings.TrimSpace(activity)) > 100 {
-			dialog.NewError(
-				fmt.Errorf("activity name too long (max 100 characters)"),
-				fyne.CurrentApp().Driver().AllWindows()[0],
-			).Show()
-			return
-		}
-		tags := []string{}
-		for _, t := range strings.FieldsFunc(tagsText, func(r rune) bool { return r == ',' || r == ' ' }) {
-			trimmed := strings.TrimSpace(t)
-			if trimmed != "" {
-				// Validate tag length
-				if len(trimmed) > 20 {
-					dialog.NewError(
-						fmt.Errorf("tag '%s' too long (max 20 characters)", trimmed),
-						fyne.CurrentApp().Driver().AllWindows()[0],
-					).Show()
-					return
-				}
-				tags = append(tags, trimmed)
-			}
-		}
-		// Limit number of tags
-		if len(tags) > 5 {
-			dialog.NewError(
-				fmt.Errorf("too many tags (max 5 allowed)"),
-				fyne.CurrentApp().Driver().AllWindows()[0],
-			).Show()
-			return
-		}
-		sess := tracker.NewSession(activity)
-		sess.Tags = tags
-		// Validate session before starting
-		if err := sess.Validate(); err != nil {
-			dialog.NewError(err, fyne.CurrentApp().Driver().AllWindows()[0]).Show()
-			return
-		}
-		ui.currentSession = sess
-		ui.isTracking = true
-		ui.notificationSent = false // Reset notification flag for new session
-		ui.startStopBtn.SetStopState(true)
-		ui.activityEntry.Disable()
-		ui.tagEntry.Disable()
-	} else {
-		ui.currentSession.Stop()
-		// Validate session before saving
-		if err := ui.currentSession.Validate(); err != nil {
-			log.SetOutput(os.Stderr)
-			log.Printf("Session validation failed: %v", err)
-			log.SetOutput(io.Discard)
-			dialog.NewError(err, fyne.CurrentApp().Driver().AllWindows()[0]).Show()
-			return
-		}
-		err := ui.storage.SaveSession(ui.currentSession)
-		if err != nil {
-			log.SetOutput(os.Stderr)
-			log.Printf("Failed to save session: %v", err)
-			log.SetOutput(io.Discard)
-			dialog.NewError(
-				fmt.Errorf("failed to save session: %v", err),
-				fyne.CurrentApp().Driver().AllWindows()[0],
-			).Show()
-			return
-		}
-		ui.sessionsToday, _ = ui.storage.LoadSessionsForDay(time.Now())
-		ui.allSessionsToday = ui.sessionsToday // Update unfiltered list
-		ui.activityList.Refresh()
-		// --- Update tab content after session ends ---
+	activity := ui.activityEntry.Text
+	tagsText := ui.tagEntry.Text
+	if activity == "" {
+		// Show error dialog for empty activity
+		dialog.NewError(
+			fmt.Errorf("activity name cannot be empty"),
+			fyne.CurrentApp().Driver().AllWindows()[0],
+		).Show()
+		return
+	}
+	// Validate activity length
+	if len(strings.TrimSpace(activity)) > 100 {
+		dialog.NewError(
+			fmt.Errorf("activity name too long (max 100 characters)"),
+			fyne.CurrentApp().Driver().AllWindows()[0],
+		).Show()
+		return
+	}
+	tags, err := parseTags(tagsText)
+	if err != nil {
+		dialog.NewError(err, fyne.CurrentApp().Driver().AllWindows()[0]).Show()
+		return
+	}
+	if ui.pomodoroActive {
+		dialog.NewError(fmt.Errorf("stop the pomodoro before starting a timer"), fyne.CurrentApp().Driver().AllWindows()[0]).Show()
+		return
+	}
+	sess := tracker.NewSession(activity)
+	// Keep any tags and billable flag added by the auto-categorization rules
+	sess.AddTags(tags...)
+	sess.Billable = sess.Billable || ui.billableCheck.Checked
+	sess.Notes = strings.TrimSpace(ui.notesEntry.Text)
+	// The engine validates the session before it starts running
+	if err := ui.engine.Start(sess); err != nil {
+		dialog.NewError(err, fyne.CurrentApp().Driver().AllWindows()[0]).Show()
+		return
+	}
+	// Clear the entries so the next timer can be started right away
+	ui.activityEntry.SetText("")
+	ui.tagEntry.SetText("")
+	ui.notesEntry.SetText("")
+	ui.billableCheck.SetChecked(false)
+}
+
+// exportOptions collects the optional export content chosen by the user for
+// an export of the days from start up to, but not including, end
+func (ui *MainUI) exportOptions(start, end time.Time) export.Options {
+	return export.Options{
+		IncludeNotes: ui.config.ExportIncludeNotes,
+		Goals:        ui.evaluateGoalsForRange(start, end),
+	}
+}
+
+// refreshSessions reloads the selected day's sessions and rebuilds the activity list and viewers
+func (ui *MainUI) refreshSessions() {
+	ui.sessionsToday, _ = ui.storage.LoadSessionsForDay(ui.viewDate)
+	ui.allSessionsToday = ui.sessionsToday // Update unfiltered list
+	ui.activityList.Refresh()
+	// Only the visible viewer is rebuilt now; the others load when opened
+	for i := range ui.viewerStale {
+		ui.viewerStale[i] = true
+	}
+	ui.showViewer(ui.selectedViewer())
+	ui.updateActivityListPlaceholder()
+	ui.updateActivitySuggestions()
+	if ui.updateAnalytics != nil {
+		ui.updateAnalytics()
+	}
+	go ui.updateGoals()
+}
+
+// showViewer shows a viewer tab, first rebuilding it if its sessions changed
+// while it was hidden. The caller must hold ui.mu.
+func (ui *MainUI) showViewer(idx int) {
+	if ui.viewerStale[idx] {
 		terminalGreen := color.RGBA{0, 255, 0, 255}
-		ui.viewerContents[0] = container.NewCenter(makeHourGrid(ui.sessionsToday, terminalGreen))
-		ui.viewerContents[1] = container.NewCenter(makeWeekGrid(ui.storage, terminalGreen))
-		ui.viewerContents[2] = container.NewCenter(makeMonthGrid(ui.storage, terminalGreen))
-		selectedTab := 0
-		for i, btn := range ui.tabBar.buttons {
-			if btn.Selected {
-				selectedTab = i
-				break
-			}
+		switch idx {
+		case viewerDaily:
+			ui.viewerContents[idx] = container.NewCenter(ui.makeDailyViewer(ui.viewDate, ui.sessionsToday, terminalGreen))
+		case viewerWeekly:
+			ui.viewerContents[idx] = container.NewCenter(makeWeekGrid(ui.storage, ui.config.OverlapPolicy, ui.viewDate, terminalGreen))
+		case viewerMonthly:
+			ui.viewerContents[idx] = container.NewCenter(makeMonthGrid(ui.storage, ui.viewDate, terminalGreen))
+		case viewerYearly:
+			ui.yearView.refresh()
+		case viewerPlan:
+			ui.viewerContents[idx] = container.NewCenter(ui.makePlanViewer(ui.viewDate, terminalGreen))
+		case viewerReports:
+			ui.reportView.refresh()
 		}
-		ui.contentContainer.Objects = []fyne.CanvasObject{ui.viewerContents[selectedTab]}
-		ui.contentContainer.Refresh()
-		for i, btn := range ui.tabBar.buttons {
-			btn.Selected = (i == selectedTab)
-			btn.Refresh()
+		ui.viewerStale[idx] = false
+	}
+	ui.contentContainer.Objects = []fyne.CanvasObject{ui.viewerContents[idx]}
+	ui.contentContainer.Refresh()
+	for i, btn := range ui.tabBar.buttons {
+		btn.Selected = (i == idx)
+		btn.Refresh()
+	}
+}
+
+// parseTags splits comma or space separated tags and enforces the tag limits
+func parseTags(tagsText string) ([]string, error) {
+	tags := []string{}
+	for _, t := range strings.FieldsFunc(tagsText, func(r rune) bool { return r == ',' || r == ' ' }) {
+		trimmed := strings.TrimSpace(t)
+		if trimmed != "" {
+			// Validate tag length
+			if len(trimmed) > 20 {
+				return nil, fmt.Errorf("tag '%s' too long (max 20 characters)", trimmed)
+			}
+			tags = append(tags, trimmed)
 		}
-		ui.isTracking = false
-		ui.startStopBtn.SetStopState(false)
-		ui.activityEntry.Enable()
-		ui.tagEntry.Enable()
-		ui.activityEntry.SetText("")
-		ui.tagEntry.SetText("")
-		ui.updateActivityListPlaceholder()
 	}
+	// Limit number of tags
+	if len(tags) > 5 {
+		return nil, fmt.Errorf("too many tags (max 5 allowed)")
+	}
+	return tags, nil
 }
 
 // Add tag filtering to activity list
@@ -709,6 +793,12 @@ func (ui *MainUI) FilterSessionsByTag(tag string) {
 
 // Cleanup properly shuts down the UI and releases resources
 func (ui *MainUI) Cleanup() {
+	// Save whatever is still being tracked before the database closes
+	if ui.engine != nil {
+		if err := ui.engine.StopAll(); err != nil {
+			log.Printf("Failed to save running sessions: %v", err)
+		}
+	}
 	if ui.storage != nil {
 		ui.storage.Close()
 	}
@@ -795,9 +885,12 @@ func (ui *MainUI) createTimeTrackerTab() *container.TabItem {
 	title.TextStyle = fyne.TextStyle{Bold: true, Monospace: true}
 	title.TextSize = 20
 
-	activityEntry := widget.NewEntry()
+	activityEntry := widget.NewSelectEntry(nil)
 	activityEntry.SetPlaceHolder("Enter activity name")
 	activityEntry.TextStyle = fyne.TextStyle{Monospace: true}
+	activityEntry.OnChanged = func(text string) {
+		ui.filterActivitySuggestions(text)
+	}
 	tagEntry := widget.NewEntry()
 	tagEntry.SetPlaceHolder("Tags (optional)")
 	tagEntry.TextStyle = fyne.TextStyle{Monospace: true}
@@ -809,95 +902,102 @@ func (ui *MainUI) createTimeTrackerTab() *container.TabItem {
 	}
 	ui.activityEntry = activityEntry
 	ui.tagEntry = tagEntry
+	ui.updateActivitySuggestions()
+	ui.billableCheck = widget.NewCheck("Billable", nil)
+	notesEntry := widget.NewMultiLineEntry()
+	notesEntry.SetPlaceHolder("Notes (optional, editable while tracking)")
+	notesEntry.Wrapping = fyne.TextWrapWord
+	notesEntry.SetMinRowsVisible(2)
+	ui.notesEntry = notesEntry
+	includeNotesCheck := widget.NewCheck("Include notes in exports", nil)
+	includeNotesCheck.SetChecked(ui.config.ExportIncludeNotes)
+	includeNotesCheck.OnChanged = func(on bool) {
+		ui.config.ExportIncludeNotes = on
+		ui.saveConfig()
+	}
 
-	exportCSV := NewTerminalButton("Export CSV", func() {
-		dialog.NewFileSave(
-			func(uc fyne.URIWriteCloser, err error) {
-				if err != nil || uc == nil {
-					return
-				}
-				sessions, _ := ui.storage.LoadSessionsForDay(time.Now())
-				export.ExportToCSV(sessions, uc.URI().Path())
-				uc.Close()
-			},
-			fyne.CurrentApp().Driver().AllWindows()[0],
-		).Show()
+	// The export menus list every registered format
+	var exportDay, exportMonth *TerminalButton
+	exportDay = NewTerminalButton("Export Day", func() {
+		day := ui.viewDate
+		ui.showExportMenu(exportDay, "katana-"+day.Format("20060102"), func() export.Report {
+			end := day.AddDate(0, 0, 1)
+			return export.Report{Title: "Tracked Sessions", Start: day, End: end, Options: ui.exportOptions(day, end)}
+		})
 	})
-	exportPDF := NewTerminalButton("Export PDF", func() {
-		dialog.NewFileSave(
-			func(uc fyne.URIWriteCloser, err error) {
-				if err != nil || uc == nil {
-					return
-				}
-				sessions, _ := ui.storage.LoadSessionsForDay(time.Now())
-				export.ExportToPDF(sessions, uc.URI().Path())
-				uc.Close()
-			},
-			fyne.CurrentApp().Driver().AllWindows()[0],
-		).Show()
+	exportMonth = NewTerminalButton("Export Month", func() {
+		month := time.Date(ui.viewDate.Year(), ui.viewDate.Month(), 1, 0, 0, 0, 0, time.Local)
+		ui.showExportMenu(exportMonth, "katana-"+month.Format("200601"), func() export.Report {
+			return export.Report{
+				Title:   fmt.Sprintf("Time Tracking Report - %s %d", month.Month(), month.Year()),
+				Start:   month,
+				End:     month.AddDate(0, 1, 0),
+				Options: ui.exportOptions(month, month.AddDate(0, 1, 0)),
+			}
+		})
 	})
 
-	exportMonthlyCSV := NewTerminalButton("Export Month CSV", func() {
-		dialog.NewFileSave(
-			func(uc fyne.URIWriteCloser, err error) {
-				if err != nil || uc == nil {
-					return
-				}
-				export.ExportMonthlyToCSV(ui.storage, uc.URI().Path())
-				uc.Close()
-			},
-			fyne.CurrentApp().Driver().AllWindows()[0],
-		).Show()
+	addEntryBtn := NewTerminalButton("Add Entry", func() {
+		ui.showAddEntryDialog()
+	})
+	historyBtn := NewTerminalButton("History", func() {
+		ui.showSessionHistory()
+	})
+	goalsBtn := NewTerminalButton("Goals", func() {
+		ui.showGoalsDialog()
+	})
+	habitsBtn := NewTerminalButton("Habits", func() {
+		ui.showHabitsDialog()
+	})
+	billingBtn := NewTerminalButton("Billing", func() {
+		ui.showBillingDialog()
+	})
+	rulesBtn := NewTerminalButton("Rules", func() {
+		ui.showRulesDialog()
 	})
 
-	exportMonthlyPDF := NewTerminalButton("Export Month PDF", func() {
-		dialog.NewFileSave(
-			func(uc fyne.URIWriteCloser, err error) {
-				if err != nil || uc == nil {
-					return
-				}
-				export.ExportMonthlyToPDF(ui.storage, uc.URI().Path())
-				uc.Close()
-			},
-			fyne.CurrentApp().Driver().AllWindows()[0],
-		).Show()
+	exportRange := NewTerminalButton("Export...", func() {
+		ui.showExportDialog()
+	})
+	importICS := NewTerminalButton("Import .ics", func() {
+		ui.showCalendarImport()
 	})
 
-	startStopBtn := NewTimerTerminalButton("Start", func() {
-		ui.toggleTracking()
+	startBtn := NewTerminalButton("Start", func() {
+		ui.startTracking()
 	})
-	ui.startStopBtn = startStopBtn
-	ui.startStopBtn.SetStopState(ui.isTracking)
+	ui.timersBox = container.NewVBox()
 
 	analyticsText := canvas.NewText("", terminalGreen)
 	analyticsText.TextStyle = fyne.TextStyle{Monospace: true}
+	// Totals for the selected day and the week and month ending on it
 	updateAnalytics := func() {
-		today := time.Now()
+		today := ui.viewDate
 		totalToday := 0.0
 		totalWeek := 0.0
 		totalMonth := 0.0
+		policy := ui.config.OverlapPolicy
 		sessionsToday, _ := ui.storage.LoadSessionsForDay(today)
-		for _, s := range sessionsToday {
-			totalToday += s.Duration.Hours()
-		}
+		totalToday = tracker.TotalTracked(sessionsToday, policy).Hours()
 		for i := 0; i < 7; i++ {
 			day := today.AddDate(0, 0, -i)
 			s, _ := ui.storage.LoadSessionsForDay(day)
-			for _, sess := range s {
-				totalWeek += sess.Duration.Hours()
-			}
+			totalWeek += tracker.TotalTracked(s, policy).Hours()
 		}
 		for i := 0; i < 30; i++ {
 			day := today.AddDate(0, 0, -i)
 			s, _ := ui.storage.LoadSessionsForDay(day)
-			for _, sess := range s {
-				totalMonth += sess.Duration.Hours()
-			}
+			totalMonth += tracker.TotalTracked(s, policy).Hours()
 		}
-		analyticsText.Text = fmt.Sprintf("Today: %.1fh | Week: %.1fh | Month: %.1fh", totalToday, totalWeek, totalMonth)
+		dayLabel := "Today"
+		if !ui.viewingToday() {
+			dayLabel = today.Format("Mon 02 Jan 2006")
+		}
+		analyticsText.Text = fmt.Sprintf("%s: %.1fh | Week: %.1fh | Month: %.1fh", dayLabel, totalToday, totalWeek, totalMonth)
 		canvas.Refresh(analyticsText)
 	}
 	updateAnalytics()
+	ui.updateAnalytics = updateAnalytics
 
 	// --- Activity List ---
 	activityListBorder := canvas.NewRectangle(terminalGreen)
@@ -945,23 +1045,27 @@ func (ui *MainUI) createTimeTrackerTab() *container.TabItem {
 	ui.updateActivityListPlaceholder()
 
 	// --- Viewers ---
-	sessionsToday, _ := ui.storage.LoadSessionsForDay(time.Now())
-	dailyGrid := container.NewCenter(makeHourGrid(sessionsToday, terminalGreen))
-	weeklyGrid := container.NewCenter(makeWeekGrid(ui.storage, terminalGreen))
-	monthlyGrid := container.NewCenter(makeMonthGrid(ui.storage, terminalGreen))
-	viewerContents := []fyne.CanvasObject{dailyGrid, weeklyGrid, monthlyGrid}
+	sessionsToday, _ := ui.storage.LoadSessionsForDay(ui.viewDate)
+	ui.timeline = newDayTimeline()
+	dailyGrid := container.NewCenter(ui.makeDailyViewer(ui.viewDate, sessionsToday, terminalGreen))
+	weeklyGrid := container.NewCenter(makeWeekGrid(ui.storage, ui.config.OverlapPolicy, ui.viewDate, terminalGreen))
+	monthlyGrid := container.NewCenter(makeMonthGrid(ui.storage, ui.viewDate, terminalGreen))
+	planGrid := container.NewCenter(ui.makePlanViewer(ui.viewDate, terminalGreen))
+	ui.yearView = ui.newYearViewer()
+	yearlyGrid := container.NewCenter(ui.yearView.content)
+	ui.reportView = ui.newReportViewer()
+	reportsGrid := container.NewCenter(ui.reportView.content)
+	viewerContents := []fyne.CanvasObject{dailyGrid, weeklyGrid, monthlyGrid, yearlyGrid, planGrid, reportsGrid}
 	ui.viewerContents = viewerContents
+	ui.viewerStale = make([]bool, len(viewerContents))
 	selectedTab := 0
 	ui.contentContainer = container.NewMax(ui.viewerContents[selectedTab])
 	ui.tabBar = NewTerminalTabBar(ui.originalTabLabels, selectedTab, func(idx int) {
-		ui.contentContainer.Objects = []fyne.CanvasObject{ui.viewerContents[idx]}
-		ui.contentContainer.Refresh()
-		for i, btn := range ui.tabBar.buttons {
-			btn.Selected = (i == idx)
-			btn.Refresh()
-		}
+		ui.mu.Lock()
+		defer ui.mu.Unlock()
+		ui.showViewer(idx)
 	})
-	centeredTabBar := container.NewCenter(ui.tabBar)
+	centeredTabBar := container.NewCenter(container.NewVBox(ui.tabBar, container.NewCenter(ui.createViewerNavigation())))
 	// Use a VSplit to allow user to resize activity list and viewers dynamically
 	centerSplit := container.NewVSplit(activityListStack, container.NewVBox(centeredTabBar, container.NewMax(ui.contentContainer)))
 	centerSplit.Offset = 0.4 // More space for activity list by default
@@ -983,16 +1087,22 @@ func (ui *MainUI) createTimeTrackerTab() *container.TabItem {
 		widget.NewSeparator(), // Additional top padding
 		widget.NewSeparator(), // Additional top padding
 		container.NewCenter(title),
+		ui.createFavoritesBar(),
 		canvas.NewText("Activity:", terminalGreen),
 		activityEntry,
 		canvas.NewText("Tags:", terminalGreen),
 		tagEntry,
+		ui.billableCheck,
+		canvas.NewText("Notes:", terminalGreen),
+		notesEntry,
 		tagFilterEntry,
-		startStopBtn,
-		container.NewGridWithColumns(2, exportCSV, exportPDF),
-		container.NewGridWithColumns(2, exportMonthlyCSV, exportMonthlyPDF),
+		container.NewGridWithColumns(5, startBtn, addEntryBtn, historyBtn, billingBtn, rulesBtn),
+		container.NewGridWithColumns(4, exportDay, exportMonth, exportRange, importICS),
+		includeNotesCheck,
 		container.NewCenter(timerText),
+		ui.timersBox,
 		analyticsText,
+		container.NewBorder(nil, nil, nil, container.NewHBox(goalsBtn, habitsBtn), ui.createGoalsPanel()),
 	)
 
 	mainContent := container.NewVSplit(
@@ -1002,7 +1112,13 @@ func (ui *MainUI) createTimeTrackerTab() *container.TabItem {
 	mainContent.Offset = 0.22 // Adjust as needed for initial split
 
 	// Start the background timer update goroutine for this tab
-	ui.startTimeTrackerUpdates(timerText, updateAnalytics)
+	ui.startTimeTrackerUpdates(timerText)
+	ui.startIdleMonitor()
+	ui.startGoalMonitor()
+	ui.startHabitMonitor()
+	if err := ui.registerTemplates(); err != nil {
+		log.Printf("Failed to load export templates: %v", err)
+	}
 
 	return container.NewTabItem("Time Tracker", mainContent)
 }
@@ -1332,6 +1448,18 @@ func (ui *MainUI) createCountdownTab() *container.TabItem {
 	// Button container
 	buttonContainer := container.NewGridWithColumns(2, startStopBtn, resetBtn)
 
+	// Templates start a countdown of their own length, replacing a running one
+	ui.startCountdown = func(d time.Duration) {
+		if countdownRunning {
+			startStopBtn.OnTap()
+		}
+		total := int(d.Seconds())
+		hoursEntry.SetText(strconv.Itoa(total / 3600))
+		minutesEntry.SetText(strconv.Itoa(total % 3600 / 60))
+		secondsEntry.SetText(strconv.Itoa(total % 60))
+		startStopBtn.OnTap()
+	}
+
 	// Progress bar
 	progressBar := widget.NewProgressBar()
 	progressBar.Min = 0
@@ -1797,50 +1925,6 @@ func (ui *MainUI) createAlarmTab() *container.TabItem {
 	return container.NewTabItem("Alarm", content)
 }
 
-// startTimeTrackerUpdates starts the background goroutine for time tracker updates
-func (ui *MainUI) startTimeTrackerUpdates(timerText *canvas.Text, updateAnalytics func()) {
-	go func() {
-		ticker := time.NewTicker(500 * time.Millisecond)
-		defer ticker.Stop()
-		lastDay := time.Now().Day()
-		for {
-			<-ticker.C
-			ui.mu.Lock()
-			if ui.isTracking && ui.currentSession != nil {
-				dur := time.Since(ui.currentSession.StartTime)
-				h := int(dur.Hours())
-				m := int(dur.Minutes()) % 60
-				s := int(dur.Seconds()) % 60
-				timerText.Text = fmt.Sprintf("%02d:%02d:%02d", h, m, s)
-				canvas.Refresh(timerText)
-				// Send notification only once when crossing 2 hours
-				if dur.Hours() >= 2 && !ui.notificationSent {
-					beeep.Notify("Katana Time Tracker", "Session running over 2 hours!", "")
-					// Play classic alarm sound for 2 seconds
-					go func() {
-						ui.soundPlayer.PlaySound("Classic Alarm 995", 2*time.Second)
-					}()
-					ui.notificationSent = true
-				}
-			} else {
-				timerText.Text = "00:00:00"
-				canvas.Refresh(timerText)
-			}
-			// Only refresh analytics and activity list if the day has changed
-			if time.Now().Day() != lastDay {
-				lastDay = time.Now().Day()
-				sessions, _ := ui.storage.LoadSessionsForDay(time.Now())
-				ui.sessionsToday = sessions
-				ui.allSessionsToday = sessions // Update unfiltered list
-				ui.activityList.Refresh()
-				updateAnalytics()
-				ui.updateActivityListPlaceholder()
-			}
-			ui.mu.Unlock()
-		}
-	}()
-}
-
 // --- CustomMainTabContainer: uses TerminalTabButton styling for main app tabs ---
 type CustomMainTabContainer struct {
 	widget.BaseWidget
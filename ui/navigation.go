@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"katana/tracker"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Viewer tabs, in TerminalTabBar order
+const (
+	viewerDaily = iota
+	viewerWeekly
+	viewerMonthly
+	viewerYearly
+	viewerPlan
+	viewerReports
+)
+
+// createViewerNavigation returns the previous/today/next buttons and date
+// picker that choose the period shown by the viewers, the activity list,
+// the analytics line and the exports
+func (ui *MainUI) createViewerNavigation() fyne.CanvasObject {
+	ui.viewDateEntry = widget.NewDateEntry()
+	ui.viewDateEntry.SetDate(&ui.viewDate)
+	ui.viewDateEntry.OnChanged = func(d *time.Time) {
+		if d != nil && !tracker.DayStart(*d).Equal(ui.viewDate) {
+			ui.setViewDate(*d)
+		}
+	}
+	prevBtn := NewTerminalButton("<", func() {
+		ui.setViewDate(ui.stepViewDate(-1))
+	})
+	todayBtn := NewTerminalButton("Today", func() {
+		ui.setViewDate(time.Now())
+	})
+	nextBtn := NewTerminalButton(">", func() {
+		ui.setViewDate(ui.stepViewDate(1))
+	})
+	return container.NewHBox(prevBtn, todayBtn, nextBtn, ui.viewDateEntry)
+}
+
+// stepViewDate moves the selected date by one period of the current viewer
+func (ui *MainUI) stepViewDate(dir int) time.Time {
+	switch ui.selectedViewer() {
+	case viewerWeekly:
+		return ui.viewDate.AddDate(0, 0, 7*dir)
+	case viewerMonthly:
+		return ui.viewDate.AddDate(0, dir, 0)
+	case viewerYearly:
+		return ui.viewDate.AddDate(dir, 0, 0)
+	case viewerReports:
+		// The report keeps its own range, which moves by its length
+		ui.reportView.shift(dir)
+		return ui.viewDate
+	default:
+		return ui.viewDate.AddDate(0, 0, dir)
+	}
+}
+
+// selectedViewer returns the index of the viewer tab being shown
+func (ui *MainUI) selectedViewer() int {
+	for i, btn := range ui.tabBar.buttons {
+		if btn.Selected {
+			return i
+		}
+	}
+	return viewerDaily
+}
+
+// setViewDate selects the day the viewers and the activity list show
+func (ui *MainUI) setViewDate(d time.Time) {
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+	ui.selectViewDate(d)
+	ui.refreshSessions()
+}
+
+// selectViewDate records the selected day and syncs the date picker and the
+// Yearly viewer. The caller must hold ui.mu and refresh the sessions.
+func (ui *MainUI) selectViewDate(d time.Time) {
+	ui.viewDate = tracker.DayStart(d)
+	if ui.yearView != nil {
+		ui.yearView.year = ui.viewDate.Year()
+	}
+	if ui.viewDateEntry != nil {
+		date := ui.viewDate
+		ui.viewDateEntry.SetDate(&date)
+	}
+}
+
+// viewingToday reports whether the selected day is today
+func (ui *MainUI) viewingToday() bool {
+	return ui.viewDate.Equal(tracker.DayStart(time.Now()))
+}
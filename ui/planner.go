@@ -0,0 +1,246 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"katana/export"
+	"katana/tracker"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+// makePlanViewer shows the day's plan over the hour grid, an adherence
+// summary and a button to edit the plan
+func (ui *MainUI) makePlanViewer(day time.Time, terminalGreen color.Color) fyne.CanvasObject {
+	dayStart := tracker.DayStart(day)
+	blocks, _ := ui.storage.LoadPlannedBlocks(dayStart, dayStart.AddDate(0, 0, 1))
+	sessions, _ := ui.storage.LoadSessionsForDay(day)
+	report := tracker.CompareDay(blocks, sessions, day)
+
+	summary := canvas.NewText(adherenceSummary(report), terminalGreen)
+	summary.TextStyle = fyne.TextStyle{Monospace: true}
+	legend := canvas.NewText("green: as planned | yellow: planned, not tracked | grey: unplanned", color.RGBA{R: 180, G: 180, B: 180, A: 255})
+	legend.TextStyle = fyne.TextStyle{Monospace: true}
+	editBtn := NewTerminalButton("Edit Plan", func() {
+		ui.showPlannerDialog(day)
+	})
+	return container.NewVBox(
+		makePlanGrid(blocks, sessions, terminalGreen),
+		container.NewCenter(summary),
+		container.NewCenter(legend),
+		container.NewCenter(editBtn),
+	)
+}
+
+// makePlanGrid overlays planned blocks on the hour grid: hours both planned
+// and tracked are green, planned but untracked hours yellow and tracked but
+// unplanned hours grey
+func makePlanGrid(blocks []tracker.PlannedBlock, sessions []*tracker.Session, terminalGreen color.Color) fyne.CanvasObject {
+	yellow := color.RGBA{R: 255, G: 255, B: 0, A: 255}
+	grey := color.RGBA{R: 180, G: 180, B: 180, A: 255}
+	planned := make(map[int]bool)
+	for _, b := range blocks {
+		markHours(planned, b.Start, b.End)
+	}
+	tracked := make(map[int]bool)
+	for _, s := range sessions {
+		markHours(tracked, s.StartTime, s.EndTime)
+	}
+	boxes := make([]fyne.CanvasObject, 24)
+	for i := 0; i < 24; i++ {
+		var rectColor, strokeColor, textColor color.Color = color.Black, terminalGreen, terminalGreen
+		switch {
+		case planned[i] && tracked[i]:
+			rectColor, textColor = terminalGreen, color.Black
+		case planned[i]:
+			strokeColor, textColor = yellow, yellow
+		case tracked[i]:
+			rectColor, textColor = grey, color.Black
+		}
+		rect := canvas.NewRectangle(rectColor)
+		rect.StrokeColor = strokeColor
+		rect.StrokeWidth = 1
+		label := canvas.NewText(fmt.Sprintf("%02d:00", i), textColor)
+		label.TextStyle = fyne.TextStyle{Monospace: true}
+		label.Alignment = fyne.TextAlignCenter
+		boxes[i] = container.NewMax(rect, container.NewCenter(label))
+	}
+	return container.NewGridWithColumns(8, boxes...)
+}
+
+// adherenceSummary describes a day's adherence on one line
+func adherenceSummary(r tracker.AdherenceReport) string {
+	if len(r.Blocks) == 0 {
+		return fmt.Sprintf("No plan | tracked %.1fh", r.Tracked.Hours())
+	}
+	return fmt.Sprintf("Planned %.1fh | as planned %.1fh (%.0f%%) | unplanned %.1fh",
+		r.Planned.Hours(), r.Matched.Hours(), r.Percent(), r.Unplanned.Hours())
+}
+
+// showPlannerDialog edits the planned blocks of a day, imports them from an
+// .ics file and reports how well the day followed the plan
+func (ui *MainUI) showPlannerDialog(day time.Time) {
+	win := fyne.CurrentApp().Driver().AllWindows()[0]
+	grey := color.RGBA{R: 180, G: 180, B: 180, A: 255}
+
+	var blocks []tracker.PlannedBlock
+	var report tracker.AdherenceReport
+	var list *widget.List
+	summary := canvas.NewText("", color.RGBA{R: 0, G: 255, B: 0, A: 255})
+	summary.TextStyle = fyne.TextStyle{Monospace: true}
+
+	dateEntry := widget.NewDateEntry()
+	dateEntry.SetDate(&day)
+	selectedDay := func() time.Time {
+		if dateEntry.Date == nil {
+			return tracker.DayStart(day)
+		}
+		return tracker.DayStart(*dateEntry.Date)
+	}
+	reload := func() {
+		start := selectedDay()
+		blocks, _ = ui.storage.LoadPlannedBlocks(start, start.AddDate(0, 0, 1))
+		sessions, _ := ui.storage.LoadSessionsForDay(start)
+		report = tracker.CompareDay(blocks, sessions, start)
+		summary.Text = adherenceSummary(report)
+		canvas.Refresh(summary)
+		list.Refresh()
+	}
+	changed := func() {
+		reload()
+		ui.mu.Lock()
+		ui.refreshSessions()
+		ui.mu.Unlock()
+	}
+
+	list = widget.NewList(
+		func() int { return len(report.Blocks) },
+		func() fyne.CanvasObject {
+			label := canvas.NewText("", grey)
+			label.TextStyle = fyne.TextStyle{Monospace: true}
+			return container.NewBorder(nil, nil, nil, NewTerminalButton("Delete", nil), label)
+		},
+		func(i int, o fyne.CanvasObject) {
+			if i >= len(report.Blocks) {
+				return
+			}
+			a := report.Blocks[i]
+			row := o.(*fyne.Container)
+			label := row.Objects[0].(*canvas.Text)
+			label.Text = fmt.Sprintf("%s-%s %-24s %3.0f%% (%s as planned, %s other)",
+				a.Block.Start.Format("15:04"), a.Block.End.Format("15:04"), a.Block.Label(),
+				a.Percent(), formatMinutesShort(a.Matched), formatMinutesShort(a.Other))
+			canvas.Refresh(label)
+			row.Objects[1].(*TerminalButton).OnTap = func() {
+				if err := ui.storage.DeletePlannedBlock(a.Block.ID); err != nil {
+					dialog.NewError(err, win).Show()
+				}
+				changed()
+			}
+		},
+	)
+	dateEntry.OnChanged = func(*time.Time) {
+		reload()
+	}
+
+	activityEntry := widget.NewEntry()
+	activityEntry.SetPlaceHolder("Activity (e.g. study:math)")
+	startEntry := widget.NewEntry()
+	startEntry.SetPlaceHolder("Start HH:MM")
+	endEntry := widget.NewEntry()
+	endEntry.SetPlaceHolder("End HH:MM")
+	addBtn := NewTerminalButton("Add Block", func() {
+		start, err := clockOnDay(selectedDay(), startEntry.Text)
+		if err != nil {
+			dialog.NewError(fmt.Errorf("invalid start time: %v", err), win).Show()
+			return
+		}
+		end, err := clockOnDay(selectedDay(), endEntry.Text)
+		if err != nil {
+			dialog.NewError(fmt.Errorf("invalid end time: %v", err), win).Show()
+			return
+		}
+		b := tracker.NewPlannedBlock(strings.TrimSpace(activityEntry.Text), start, end)
+		if err := b.Validate(); err != nil {
+			dialog.NewError(err, win).Show()
+			return
+		}
+		if err := ui.storage.SavePlannedBlocks([]*tracker.PlannedBlock{&b}); err != nil {
+			dialog.NewError(err, win).Show()
+			return
+		}
+		activityEntry.SetText("")
+		startEntry.SetText("")
+		endEntry.SetText("")
+		changed()
+	})
+	importBtn := NewTerminalButton("Import .ics", func() {
+		open := dialog.NewFileOpen(func(rc fyne.URIReadCloser, err error) {
+			if err != nil || rc == nil {
+				return
+			}
+			defer rc.Close()
+			count, err := ui.importPlanFromICS(rc)
+			if err != nil {
+				dialog.NewError(err, win).Show()
+				return
+			}
+			changed()
+			dialog.NewInformation("Import Calendar", fmt.Sprintf("Imported %d planned blocks.", count), win).Show()
+		}, win)
+		open.SetFilter(storage.NewExtensionFileFilter([]string{".ics"}))
+		open.Show()
+	})
+
+	form := container.NewVBox(
+		container.NewGridWithColumns(3, activityEntry, startEntry, endEntry),
+		container.NewGridWithColumns(2, addBtn, importBtn),
+	)
+	listScroll := container.NewVScroll(list)
+	listScroll.SetMinSize(fyne.NewSize(600, 200))
+	content := container.NewBorder(container.NewVBox(dateEntry, summary), form, nil, nil, listScroll)
+	reload()
+
+	d := dialog.NewCustom("Day Planner", "Close", content, win)
+	d.Resize(fyne.NewSize(720, 480))
+	d.Show()
+}
+
+// importPlanFromICS saves the timed events of an iCalendar file as planned
+// blocks and returns how many were imported. The event summary is read in
+// the activity entry syntax; its first category is used when the summary
+// names none.
+func (ui *MainUI) importPlanFromICS(r fyne.URIReadCloser) (int, error) {
+	events, err := export.ReadICS(r)
+	if err != nil {
+		return 0, err
+	}
+	var blocks []*tracker.PlannedBlock
+	for _, ev := range events {
+		b := export.PlannedBlockFromEvent(ev)
+		if b.Validate() != nil {
+			continue
+		}
+		blocks = append(blocks, &b)
+	}
+	if len(blocks) == 0 {
+		return 0, fmt.Errorf("no timed events found in the calendar")
+	}
+	return len(blocks), ui.storage.SavePlannedBlocks(blocks)
+}
+
+// formatMinutesShort renders a duration as e.g. "1h05m" or "45m"
+func formatMinutesShort(d time.Duration) string {
+	m := int(d.Round(time.Minute).Minutes())
+	if m >= 60 {
+		return fmt.Sprintf("%dh%02dm", m/60, m%60)
+	}
+	return fmt.Sprintf("%dm", m)
+}
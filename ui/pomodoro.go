@@ -0,0 +1,318 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"katana/tracker"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/gen2brain/beeep"
+)
+
+// Sounds played when a Pomodoro phase changes
+const (
+	pomodoroBreakSound = "Interface Hint Notification 911"
+	pomodoroWorkSound  = "Digital Clock Digital Alarm Buzzer 992"
+)
+
+// createPomodoroTab creates the Pomodoro tab. Each work interval runs in the
+// tracking engine as a session with the Time Tracker's activity and tags, so
+// it shows among the timers, counts toward goals and is paused when idle.
+func (ui *MainUI) createPomodoroTab() *container.TabItem {
+	terminalGreen := color.RGBA{R: 0, G: 255, B: 0, A: 255}
+
+	var pomoMu sync.Mutex
+	var pomo *tracker.Pomodoro
+	var work *tracker.Session // The run's work interval in the engine, nil during breaks
+
+	phaseLabel := canvas.NewText("Ready", terminalGreen)
+	phaseLabel.TextStyle = fyne.TextStyle{Bold: true, Monospace: true}
+	phaseLabel.TextSize = 20
+	phaseLabel.Alignment = fyne.TextAlignCenter
+
+	pomoDisplay := canvas.NewText("25:00", terminalGreen)
+	pomoDisplay.TextStyle = fyne.TextStyle{Bold: true, Monospace: true}
+	pomoDisplay.TextSize = 48
+	pomoDisplay.Alignment = fyne.TextAlignCenter
+
+	cycleLabel := canvas.NewText("", terminalGreen)
+	cycleLabel.TextStyle = fyne.TextStyle{Monospace: true}
+	cycleLabel.Alignment = fyne.TextAlignCenter
+
+	activityLabel := canvas.NewText("Uses the activity and tags from the Time Tracker tab", color.RGBA{R: 180, G: 180, B: 180, A: 255})
+	activityLabel.TextStyle = fyne.TextStyle{Monospace: true, Italic: true}
+	activityLabel.Alignment = fyne.TextAlignCenter
+
+	newMinutesEntry := func(value int) *widget.Entry {
+		e := widget.NewEntry()
+		e.SetText(fmt.Sprintf("%d", value))
+		e.TextStyle = fyne.TextStyle{Monospace: true}
+		return e
+	}
+	workEntry := newMinutesEntry(ui.config.PomodoroWorkMinutes)
+	shortEntry := newMinutesEntry(ui.config.PomodoroShortBreakMinutes)
+	longEntry := newMinutesEntry(ui.config.PomodoroLongBreakMinutes)
+	cyclesEntry := newMinutesEntry(ui.config.PomodoroCycles)
+	settingsEntries := []*widget.Entry{workEntry, shortEntry, longEntry, cyclesEntry}
+
+	settingsForm := container.NewGridWithColumns(4,
+		canvas.NewText("Work", terminalGreen),
+		canvas.NewText("Short", terminalGreen),
+		canvas.NewText("Long", terminalGreen),
+		canvas.NewText("Cycles", terminalGreen),
+		workEntry, shortEntry, longEntry, cyclesEntry,
+	)
+
+	todayLabel := canvas.NewText("", terminalGreen)
+	todayLabel.TextStyle = fyne.TextStyle{Monospace: true}
+	todayLabel.Alignment = fyne.TextAlignCenter
+	updateTodayCount := func() {
+		sessions, _ := ui.storage.LoadSessionsForDay(time.Now())
+		todayLabel.Text = fmt.Sprintf("Completed today: %d", tracker.CountPomodoros(sessions))
+		canvas.Refresh(todayLabel)
+	}
+	updateTodayCount()
+
+	// startWork runs a work interval in the engine
+	startWork := func(p *tracker.Pomodoro) error {
+		sess := tracker.NewSession(p.Activity)
+		sess.AddTags(p.Tags...)
+		if err := ui.engine.Start(sess); err != nil {
+			return err
+		}
+		pomoMu.Lock()
+		work = sess
+		pomoMu.Unlock()
+		return nil
+	}
+
+	// stopWork stops the running work interval, which the engine saves;
+	// worked is the interval as the Pomodoro saw it and decides whether it
+	// counts as a completed pomodoro
+	stopWork := func(worked *tracker.Session) {
+		pomoMu.Lock()
+		sess := work
+		work = nil
+		pomoMu.Unlock()
+		if sess == nil {
+			return
+		}
+		ui.engine.SetPomodoro(sess, worked != nil && worked.Pomodoro)
+		if err := ui.engine.Stop(sess); err != nil {
+			log.Printf("Failed to save pomodoro session: %v", err)
+		}
+		updateTodayCount()
+	}
+
+	updateDisplay := func() {
+		pomoMu.Lock()
+		defer pomoMu.Unlock()
+		if pomo == nil {
+			phaseLabel.Text = "Ready"
+			pomoDisplay.Text = fmt.Sprintf("%02d:00", parseIntSafe(workEntry.Text))
+			cycleLabel.Text = ""
+		} else {
+			remaining := pomo.Remaining(time.Now())
+			phaseLabel.Text = pomo.Phase.String()
+			pomoDisplay.Text = fmt.Sprintf("%02d:%02d", int(remaining.Minutes()), int(remaining.Seconds())%60)
+			cycleLabel.Text = fmt.Sprintf("Pomodoro %d of %d | %s", pomo.Completed+1, pomo.Settings.Cycles, pomo.Activity)
+		}
+		canvas.Refresh(phaseLabel)
+		canvas.Refresh(pomoDisplay)
+		canvas.Refresh(cycleLabel)
+	}
+
+	startStopBtn := NewTerminalButton("Start", nil)
+
+	// resetRun returns the tab to its idle state after a run ends
+	resetRun := func() {
+		ui.mu.Lock()
+		ui.pomodoroActive = false
+		ui.mu.Unlock()
+		startStopBtn.SetLabel("Start")
+		for _, e := range settingsEntries {
+			e.Enable()
+		}
+		updateDisplay()
+	}
+
+	// The phase clock follows idle pauses of the work interval, and stopping
+	// the work interval from its timer row ends the run. The idle monitor
+	// publishes while holding ui.mu, so this must not take it.
+	ui.engine.Subscribe(func(ev tracker.Event) {
+		pomoMu.Lock()
+		if pomo == nil || work == nil || ev.Session != work {
+			pomoMu.Unlock()
+			return
+		}
+		switch ev.Kind {
+		case tracker.EventPaused:
+			pomo.Pause(ev.Time)
+		case tracker.EventResumed:
+			pomo.Resume(ev.Time, ev.Kept)
+		}
+		stopped := ev.Kind == tracker.EventStopped
+		if stopped {
+			pomo, work = nil, nil
+		}
+		pomoMu.Unlock()
+		if !stopped {
+			return
+		}
+		fyne.Do(func() {
+			updateTodayCount()
+			resetRun()
+		})
+	})
+	startStopBtn.OnTap = func() {
+		pomoMu.Lock()
+		running := pomo != nil
+		pomoMu.Unlock()
+
+		if running {
+			pomoMu.Lock()
+			worked := pomo.Stop(time.Now())
+			pomo = nil
+			pomoMu.Unlock()
+			stopWork(worked)
+			resetRun()
+			return
+		}
+
+		win := fyne.CurrentApp().Driver().AllWindows()[0]
+		ui.mu.Lock()
+		tracking := ui.isTracking()
+		activity := strings.TrimSpace(ui.activityEntry.Text)
+		tagsText := ui.tagEntry.Text
+		ui.mu.Unlock()
+		if tracking {
+			dialog.NewError(fmt.Errorf("stop the running timers before starting a pomodoro"), win).Show()
+			return
+		}
+		if activity == "" {
+			dialog.NewError(fmt.Errorf("enter an activity on the Time Tracker tab first"), win).Show()
+			return
+		}
+		tags, err := parseTags(tagsText)
+		if err != nil {
+			dialog.NewError(err, win).Show()
+			return
+		}
+		settings := tracker.PomodoroSettings{
+			Work:       time.Duration(parseIntSafe(workEntry.Text)) * time.Minute,
+			ShortBreak: time.Duration(parseIntSafe(shortEntry.Text)) * time.Minute,
+			LongBreak:  time.Duration(parseIntSafe(longEntry.Text)) * time.Minute,
+			Cycles:     parseIntSafe(cyclesEntry.Text),
+		}
+		if err := settings.Validate(); err != nil {
+			dialog.NewError(err, win).Show()
+			return
+		}
+
+		// Remember the chosen lengths for next time
+		ui.config.PomodoroWorkMinutes = parseIntSafe(workEntry.Text)
+		ui.config.PomodoroShortBreakMinutes = parseIntSafe(shortEntry.Text)
+		ui.config.PomodoroLongBreakMinutes = parseIntSafe(longEntry.Text)
+		ui.config.PomodoroCycles = settings.Cycles
+		ui.saveConfig()
+
+		p := tracker.NewPomodoro(settings, activity, tags, time.Now())
+		if err := startWork(p); err != nil {
+			dialog.NewError(err, win).Show()
+			return
+		}
+		pomoMu.Lock()
+		pomo = p
+		pomoMu.Unlock()
+		ui.mu.Lock()
+		ui.pomodoroActive = true
+		ui.mu.Unlock()
+		for _, e := range settingsEntries {
+			e.Disable()
+		}
+		startStopBtn.SetLabel("Stop")
+		go ui.soundPlayer.PlaySound(pomodoroWorkSound, 2*time.Second)
+		updateDisplay()
+	}
+
+	// advance moves to the next phase, saving the work interval that just
+	// ended or starting the next one. It runs on the Fyne thread; unless
+	// skipping, it does nothing if the phase is not due, since the ticker may
+	// queue it again before an earlier advance has run.
+	advance := func(skip bool) {
+		pomoMu.Lock()
+		if pomo == nil || (!skip && pomo.Remaining(time.Now()) > 0) {
+			pomoMu.Unlock()
+			return
+		}
+		p := pomo
+		worked := p.Advance(time.Now())
+		phase := p.Phase
+		pomoMu.Unlock()
+
+		if phase == tracker.PhaseWork {
+			if err := startWork(p); err != nil {
+				log.Printf("Failed to start pomodoro work interval: %v", err)
+			}
+			go beeep.Notify("Katana Pomodoro", "Break over - back to work!", "")
+			go ui.soundPlayer.PlaySound(pomodoroWorkSound, 2*time.Second)
+		} else {
+			stopWork(worked)
+			go beeep.Notify("Katana Pomodoro", fmt.Sprintf("Time for a %s!", strings.ToLower(phase.String())), "")
+			go ui.soundPlayer.PlaySound(pomodoroBreakSound, 2*time.Second)
+		}
+		updateDisplay()
+	}
+
+	skipBtn := NewTerminalButton("Skip", func() { advance(true) })
+
+	go func() {
+		ticker := time.NewTicker(250 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			<-ticker.C
+			pomoMu.Lock()
+			due := pomo != nil && pomo.Remaining(time.Now()) <= 0
+			running := pomo != nil
+			pomoMu.Unlock()
+			if due {
+				fyne.Do(func() { advance(false) })
+			} else if running {
+				fyne.Do(updateDisplay)
+			}
+		}
+	}()
+
+	updateDisplay()
+
+	centeredContent := container.NewCenter(
+		container.NewVBox(
+			widget.NewSeparator(),
+			widget.NewSeparator(), // Additional top padding
+			widget.NewSeparator(), // Additional top padding
+			widget.NewSeparator(), // Additional top padding
+			widget.NewSeparator(), // Additional top padding
+			widget.NewSeparator(), // Additional top padding
+			container.NewCenter(canvas.NewText("Lengths in minutes:", terminalGreen)),
+			settingsForm,
+			widget.NewSeparator(),
+			container.NewCenter(phaseLabel),
+			container.NewCenter(pomoDisplay),
+			container.NewCenter(cycleLabel),
+			container.NewCenter(container.NewGridWithColumns(2, startStopBtn, skipBtn)),
+			widget.NewSeparator(),
+			container.NewCenter(todayLabel),
+			container.NewCenter(activityLabel),
+		),
+	)
+
+	return container.NewTabItem("Pomodoro", centeredContent)
+}
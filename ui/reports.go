@@ -0,0 +1,341 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"katana/tracker"
+	"math"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// reportTopTags is how many tags the Reports view ranks
+const reportTopTags = 8
+
+// reportPalette colors the categories of the Reports view, longest first;
+// categories beyond it share reportOtherColor
+var reportPalette = []color.Color{
+	color.RGBA{R: 0, G: 255, B: 0, A: 255},
+	color.RGBA{R: 0, G: 200, B: 255, A: 255},
+	color.RGBA{R: 255, G: 255, B: 0, A: 255},
+	color.RGBA{R: 255, G: 0, B: 255, A: 255},
+	color.RGBA{R: 255, G: 140, B: 0, A: 255},
+	color.RGBA{R: 80, G: 120, B: 255, A: 255},
+	color.RGBA{R: 255, G: 80, B: 80, A: 255},
+}
+
+var reportOtherColor = color.RGBA{R: 100, G: 100, B: 100, A: 255}
+
+// reportViewer is the Reports viewer: charts of the sessions in a date range.
+// It keeps its range across refreshes.
+type reportViewer struct {
+	ui      *MainUI
+	from    time.Time // First day of the range
+	to      time.Time // Last day of the range, inclusive
+	content *fyne.Container
+}
+
+// newReportViewer creates the Reports viewer showing the last 7 days
+func (ui *MainUI) newReportViewer() *reportViewer {
+	today := tracker.DayStart(time.Now())
+	v := &reportViewer{ui: ui, from: today.AddDate(0, 0, -6), to: today, content: container.NewMax()}
+	v.refresh()
+	return v
+}
+
+// setRange selects the days shown, swapping them if given in reverse
+func (v *reportViewer) setRange(from, to time.Time) {
+	from, to = tracker.DayStart(from), tracker.DayStart(to)
+	if to.Before(from) {
+		from, to = to, from
+	}
+	v.from, v.to = from, to
+	v.refresh()
+}
+
+// shift moves the range back or forward by its own length
+func (v *reportViewer) shift(dir int) {
+	days := int(v.to.Sub(v.from).Hours()/24+0.5) + 1
+	v.setRange(v.from.AddDate(0, 0, days*dir), v.to.AddDate(0, 0, days*dir))
+}
+
+// refresh reloads the range's sessions and redraws the charts
+func (v *reportViewer) refresh() {
+	terminalGreen := color.RGBA{R: 0, G: 255, B: 0, A: 255}
+	grey := color.RGBA{R: 180, G: 180, B: 180, A: 255}
+
+	end := v.to.AddDate(0, 0, 1)
+	sessions, _ := v.ui.storage.LoadSessionsInRange(v.from, end)
+	stats := tracker.ComputeRangeStats(sessions, v.from, end, v.ui.config.OverlapPolicy)
+	categories := stats.Categories()
+	colors := categoryColors(categories)
+
+	fromEntry := widget.NewDateEntry()
+	fromEntry.SetDate(&v.from)
+	toEntry := widget.NewDateEntry()
+	toEntry.SetDate(&v.to)
+	fromEntry.OnChanged = func(d *time.Time) {
+		if d != nil && !tracker.DayStart(*d).Equal(v.from) {
+			v.setRange(*d, v.to)
+		}
+	}
+	toEntry.OnChanged = func(d *time.Time) {
+		if d != nil && !tracker.DayStart(*d).Equal(v.to) {
+			v.setRange(v.from, *d)
+		}
+	}
+	today := tracker.DayStart(time.Now())
+	presets := container.NewHBox(
+		NewTerminalButton("7d", func() { v.setRange(today.AddDate(0, 0, -6), today) }),
+		NewTerminalButton("30d", func() { v.setRange(today.AddDate(0, 0, -29), today) }),
+		NewTerminalButton("Month", func() {
+			first := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.Local)
+			v.setRange(first, first.AddDate(0, 1, -1))
+		}),
+		NewTerminalButton("Year", func() {
+			first := time.Date(today.Year(), time.January, 1, 0, 0, 0, 0, time.Local)
+			v.setRange(first, first.AddDate(1, 0, -1))
+		}),
+	)
+	rangeBar := container.NewHBox(reportText("From", grey), fromEntry, reportText("To", grey), toEntry, presets)
+
+	avg := time.Duration(0)
+	if len(stats.Days) > 0 {
+		avg = stats.Total / time.Duration(len(stats.Days))
+	}
+	summary := reportText(fmt.Sprintf("%d days | total %s | avg %s/day", len(stats.Days),
+		formatMinutesShort(stats.Total), formatMinutesShort(avg)), terminalGreen)
+	summary.TextStyle = fyne.TextStyle{Bold: true, Monospace: true}
+
+	if stats.Total == 0 {
+		empty := reportText("Nothing tracked in this range", grey)
+		empty.TextStyle = fyne.TextStyle{Italic: true, Monospace: true}
+		v.content.Objects = []fyne.CanvasObject{container.NewVBox(rangeBar, summary, empty)}
+		v.content.Refresh()
+		return
+	}
+
+	v.content.Objects = []fyne.CanvasObject{container.NewVBox(
+		rangeBar,
+		summary,
+		reportText("Hours per day by category", grey),
+		makeStackedBars(stats, categories, colors),
+		container.NewHBox(
+			container.NewVBox(reportText("Category share", grey),
+				container.NewHBox(makeDonut(stats, categories, colors), makeCategoryLegend(stats, categories, colors))),
+			container.NewVBox(reportText("Top tags", grey), makeTopTags(stats)),
+			container.NewVBox(reportText("Hour of day", grey), makeHourHistogram(stats)),
+		),
+	)}
+	v.content.Refresh()
+}
+
+// categoryColors assigns the palette to categories in the order given
+func categoryColors(categories []string) map[string]color.Color {
+	colors := make(map[string]color.Color, len(categories))
+	for i, c := range categories {
+		if i < len(reportPalette) {
+			colors[c] = reportPalette[i]
+		} else {
+			colors[c] = reportOtherColor
+		}
+	}
+	return colors
+}
+
+// categoryName is how a category is labelled in reports
+func categoryName(c string) string {
+	if c == "" {
+		return "uncategorized"
+	}
+	return c
+}
+
+// reportText is a small monospace label of the Reports view
+func reportText(text string, c color.Color) *canvas.Text {
+	t := canvas.NewText(text, c)
+	t.TextStyle = fyne.TextStyle{Monospace: true}
+	t.TextSize = 11
+	return t
+}
+
+// fixedChart places objects at absolute positions inside a chart of the given size
+func fixedChart(width, height float32, objects ...fyne.CanvasObject) fyne.CanvasObject {
+	sizer := canvas.NewRectangle(color.Transparent)
+	sizer.SetMinSize(fyne.NewSize(width, height))
+	return container.NewStack(sizer, container.NewWithoutLayout(objects...))
+}
+
+// place moves and sizes an object within a fixedChart
+func place(o fyne.CanvasObject, x, y, w, h float32) fyne.CanvasObject {
+	o.Move(fyne.NewPos(x, y))
+	o.Resize(fyne.NewSize(w, h))
+	return o
+}
+
+// makeStackedBars draws one bar per day, split by category, with an hour axis
+func makeStackedBars(stats tracker.RangeStats, categories []string, colors map[string]color.Color) fyne.CanvasObject {
+	const width, height, axis, footer = 640, 150, 36, 16
+	grey := color.RGBA{R: 180, G: 180, B: 180, A: 255}
+	axisColor := color.RGBA{R: 40, G: 80, B: 40, A: 255}
+
+	// Scale to the fullest stack, which may exceed the day total when sessions overlap
+	var max time.Duration
+	for _, d := range stats.Days {
+		var sum time.Duration
+		for _, c := range d.ByCategory {
+			sum += c
+		}
+		if sum > max {
+			max = sum
+		}
+	}
+	hours := math.Max(1, math.Ceil(max.Hours()))
+	plot := float32(height - footer)
+	slot := float32(width-axis) / float32(len(stats.Days))
+	barW := slot - 2
+	if barW < 1 {
+		barW = 1
+	}
+
+	var objects []fyne.CanvasObject
+	for _, h := range []float64{0, hours / 2, hours} {
+		y := plot - float32(h/hours)*plot
+		objects = append(objects,
+			place(canvas.NewLine(axisColor), axis, y, width-axis, 0),
+			place(reportText(fmt.Sprintf("%4.1fh", h), grey), 0, y-7, axis, 12))
+	}
+	for i, d := range stats.Days {
+		x := axis + float32(i)*slot
+		y := plot
+		for _, c := range categories {
+			part := d.ByCategory[c]
+			if part <= 0 {
+				continue
+			}
+			h := float32(part.Hours()/hours) * plot
+			y -= h
+			objects = append(objects, place(canvas.NewRectangle(colors[c]), x, y, barW, h))
+		}
+	}
+	// Label the first, middle and last day
+	for _, i := range []int{0, len(stats.Days) / 2, len(stats.Days) - 1} {
+		x := float32(math.Min(float64(axis+float32(i)*slot), width-50))
+		objects = append(objects, place(reportText(stats.Days[i].Date.Format("02 Jan"), grey), x, plot+2, 50, 12))
+	}
+	return fixedChart(width, height, objects...)
+}
+
+// makeDonut draws the category share as a ring
+func makeDonut(stats tracker.RangeStats, categories []string, colors map[string]color.Color) fyne.CanvasObject {
+	const size = 130
+	var sum time.Duration
+	for _, c := range categories {
+		sum += stats.ByCategory[c]
+	}
+	// Cumulative end of each category's slice, as a fraction of the ring
+	ends := make([]float64, len(categories))
+	var acc time.Duration
+	for i, c := range categories {
+		acc += stats.ByCategory[c]
+		ends[i] = float64(acc) / float64(sum)
+	}
+	ring := canvas.NewRasterWithPixels(func(x, y, w, h int) color.Color {
+		cx, cy := float64(w)/2, float64(h)/2
+		outer := math.Min(cx, cy)
+		dx, dy := float64(x)-cx, float64(y)-cy
+		r := math.Hypot(dx, dy)
+		if r > outer || r < outer*0.55 {
+			return color.Transparent
+		}
+		// Clockwise from 12 o'clock
+		a := math.Atan2(dx, -dy)
+		if a < 0 {
+			a += 2 * math.Pi
+		}
+		f := a / (2 * math.Pi)
+		for i, end := range ends {
+			if f <= end {
+				return colors[categories[i]]
+			}
+		}
+		return reportOtherColor
+	})
+	ring.SetMinSize(fyne.NewSize(size, size))
+	return ring
+}
+
+// makeCategoryLegend lists the categories with their color, time and share
+func makeCategoryLegend(stats tracker.RangeStats, categories []string, colors map[string]color.Color) fyne.CanvasObject {
+	var sum time.Duration
+	for _, c := range categories {
+		sum += stats.ByCategory[c]
+	}
+	rows := container.NewVBox()
+	for _, c := range categories {
+		swatch := canvas.NewRectangle(colors[c])
+		swatch.SetMinSize(fyne.NewSize(10, 10))
+		d := stats.ByCategory[c]
+		label := reportText(fmt.Sprintf("%-14s %7s %3.0f%%", categoryName(c), formatMinutesShort(d), 100*float64(d)/float64(sum)),
+			color.RGBA{R: 180, G: 180, B: 180, A: 255})
+		rows.Add(container.NewHBox(container.NewCenter(swatch), label))
+	}
+	return rows
+}
+
+// makeTopTags ranks the most tracked tags with proportional bars
+func makeTopTags(stats tracker.RangeStats) fyne.CanvasObject {
+	const barMax = 90
+	terminalGreen := color.RGBA{R: 0, G: 255, B: 0, A: 255}
+	grey := color.RGBA{R: 180, G: 180, B: 180, A: 255}
+	tags := stats.TopTags(reportTopTags)
+	if len(tags) == 0 {
+		none := reportText("No tags", grey)
+		none.TextStyle = fyne.TextStyle{Italic: true, Monospace: true}
+		return none
+	}
+	rows := container.NewVBox()
+	for _, t := range tags {
+		bar := canvas.NewRectangle(terminalGreen)
+		bar.SetMinSize(fyne.NewSize(float32(barMax*t.Total.Seconds()/tags[0].Total.Seconds()), 8))
+		spacer := canvas.NewRectangle(color.Transparent)
+		spacer.SetMinSize(fyne.NewSize(barMax, 8))
+		rows.Add(container.NewHBox(
+			reportText(fmt.Sprintf("%-12s", "#"+t.Tag), grey),
+			container.NewStack(spacer, container.NewHBox(bar)),
+			reportText(formatMinutesShort(t.Total), grey),
+		))
+	}
+	return rows
+}
+
+// makeHourHistogram draws the tracked time per hour of the day
+func makeHourHistogram(stats tracker.RangeStats) fyne.CanvasObject {
+	const width, height, footer = 264, 120, 16
+	terminalGreen := color.RGBA{R: 0, G: 255, B: 0, A: 255}
+	grey := color.RGBA{R: 180, G: 180, B: 180, A: 255}
+
+	var max time.Duration
+	for _, d := range stats.ByHour {
+		if d > max {
+			max = d
+		}
+	}
+	plot := float32(height - footer)
+	slot := float32(width) / 24
+	objects := []fyne.CanvasObject{place(canvas.NewLine(color.RGBA{R: 40, G: 80, B: 40, A: 255}), 0, plot, width, 0)}
+	for h, d := range stats.ByHour {
+		if d > 0 && max > 0 {
+			barH := float32(d.Seconds()/max.Seconds()) * plot
+			objects = append(objects, place(canvas.NewRectangle(terminalGreen), float32(h)*slot+1, plot-barH, slot-2, barH))
+		}
+		if h%6 == 0 {
+			objects = append(objects, place(reportText(fmt.Sprintf("%02d", h), grey), float32(h)*slot, plot+2, 20, 12))
+		}
+	}
+	return fixedChart(width, height, objects...)
+}
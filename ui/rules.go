@@ -0,0 +1,191 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"io"
+	"katana/config"
+	"katana/tracker"
+	"log"
+	"os"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// loadRules reads the rules file and installs the rules for new sessions.
+// An invalid file disables the rules until it is fixed and reloaded.
+func (ui *MainUI) loadRules() error {
+	rs, err := config.LoadRules()
+	if err != nil {
+		log.SetOutput(os.Stderr)
+		log.Printf("Auto-categorization disabled: %v", err)
+		log.SetOutput(io.Discard)
+	}
+	ui.rules = rs
+	tracker.SetDefaultRules(rs)
+	return err
+}
+
+// showRulesDialog lists the auto-categorization rules and re-applies them to past sessions
+func (ui *MainUI) showRulesDialog() {
+	win := fyne.CurrentApp().Driver().AllWindows()[0]
+	grey := color.RGBA{R: 180, G: 180, B: 180, A: 255}
+
+	var rules []tracker.Rule
+	if ui.rules != nil {
+		rules = ui.rules.Rules()
+	}
+	list := widget.NewList(
+		func() int { return len(rules) },
+		func() fyne.CanvasObject {
+			label := canvas.NewText("", grey)
+			label.TextStyle = fyne.TextStyle{Monospace: true}
+			return label
+		},
+		func(i int, o fyne.CanvasObject) {
+			if i >= len(rules) {
+				return
+			}
+			label := o.(*canvas.Text)
+			label.Text = ruleSummary(rules[i])
+			canvas.Refresh(label)
+		},
+	)
+
+	pathLabel := canvas.NewText("Rules file: "+config.RulesPath, grey)
+	pathLabel.TextStyle = fyne.TextStyle{Monospace: true}
+
+	reloadBtn := NewTerminalButton("Reload", func() {
+		if err := ui.loadRules(); err != nil {
+			dialog.NewError(err, win).Show()
+		}
+		rules = nil
+		if ui.rules != nil {
+			rules = ui.rules.Rules()
+		}
+		list.Refresh()
+	})
+	previewBtn := NewTerminalButton("Apply to History", func() {
+		if ui.rules == nil {
+			dialog.NewError(fmt.Errorf("fix the rules file and reload it first"), win).Show()
+			return
+		}
+		sessions, err := ui.storage.GetAllSessions()
+		if err != nil {
+			dialog.NewError(err, win).Show()
+			return
+		}
+		ui.showRulePreview(ui.rules.Preview(sessions))
+	})
+
+	listScroll := container.NewVScroll(list)
+	listScroll.SetMinSize(fyne.NewSize(520, 200))
+	content := container.NewBorder(pathLabel, container.NewGridWithColumns(2, reloadBtn, previewBtn), nil, nil, listScroll)
+
+	d := dialog.NewCustom("Auto-Categorization Rules", "Close", content, win)
+	d.Resize(fyne.NewSize(620, 420))
+	d.Show()
+}
+
+// showRulePreview lists the changes the rules would make to stored sessions
+// and saves them only once confirmed
+func (ui *MainUI) showRulePreview(changes []tracker.RuleChange) {
+	win := fyne.CurrentApp().Driver().AllWindows()[0]
+	if len(changes) == 0 {
+		dialog.NewInformation("Apply Rules", "The rules would not change any session.", win).Show()
+		return
+	}
+
+	list := widget.NewList(
+		func() int { return len(changes) },
+		func() fyne.CanvasObject {
+			before := canvas.NewText("", color.RGBA{R: 180, G: 180, B: 180, A: 255})
+			before.TextStyle = fyne.TextStyle{Monospace: true}
+			after := canvas.NewText("", color.RGBA{R: 0, G: 255, B: 0, A: 255})
+			after.TextStyle = fyne.TextStyle{Monospace: true}
+			return container.NewVBox(before, after)
+		},
+		func(i int, o fyne.CanvasObject) {
+			if i >= len(changes) {
+				return
+			}
+			c := changes[i]
+			box := o.(*fyne.Container)
+			before := box.Objects[0].(*canvas.Text)
+			before.Text = c.Before.StartTime.Format("2006-01-02 15:04") + "  " + sessionSummary(c.Before)
+			after := box.Objects[1].(*canvas.Text)
+			after.Text = "              -> " + sessionSummary(c.After)
+			canvas.Refresh(before)
+			canvas.Refresh(after)
+		},
+	)
+	listScroll := container.NewVScroll(list)
+	listScroll.SetMinSize(fyne.NewSize(560, 280))
+
+	title := fmt.Sprintf("Apply Rules (%d sessions)", len(changes))
+	confirm := dialog.NewCustomConfirm(title, "Apply", "Cancel", listScroll, func(ok bool) {
+		if !ok {
+			return
+		}
+		updated := make([]*tracker.Session, len(changes))
+		for i, c := range changes {
+			updated[i] = c.After
+		}
+		if err := ui.storage.ReplaceSessions(nil, nil, updated); err != nil {
+			dialog.NewError(err, win).Show()
+			return
+		}
+		ui.mu.Lock()
+		ui.refreshSessions()
+		ui.mu.Unlock()
+		dialog.NewInformation("Apply Rules", fmt.Sprintf("Updated %d sessions.", len(updated)), win).Show()
+	}, win)
+	confirm.Resize(fyne.NewSize(640, 440))
+	confirm.Show()
+}
+
+// ruleSummary describes a rule on one line for the rules list
+func ruleSummary(r tracker.Rule) string {
+	syntax := r.Syntax
+	if syntax == "" {
+		syntax = tracker.RuleGlob
+	}
+	var effects []string
+	if r.Activity != "" {
+		effects = append(effects, "activity="+r.Activity)
+	}
+	if r.Category != "" {
+		effects = append(effects, "category="+r.Category)
+	}
+	for _, tag := range r.Tags {
+		effects = append(effects, "#"+tag)
+	}
+	if r.Project != "" {
+		effects = append(effects, "@"+r.Project)
+	}
+	if r.Billable {
+		effects = append(effects, "billable")
+	}
+	text := fmt.Sprintf("%s %q -> %s", syntax, r.Pattern, strings.Join(effects, " "))
+	if r.Name != "" {
+		text = r.Name + ": " + text
+	}
+	return text
+}
+
+// sessionSummary shows the fields rules can change
+func sessionSummary(s *tracker.Session) string {
+	text := timerTitle(s)
+	if s.Project != "" {
+		text += " @" + s.Project
+	}
+	if s.Billable {
+		text += " $"
+	}
+	return text
+}
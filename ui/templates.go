@@ -0,0 +1,257 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"katana/tracker"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	// recentActivityDays is how far back the activity autocomplete looks
+	recentActivityDays = 30
+	// maxRecentActivities limits the autocomplete suggestions
+	maxRecentActivities = 50
+)
+
+// templateShortcutKeys start the first nine templates with Ctrl+1 to Ctrl+9
+var templateShortcutKeys = []fyne.KeyName{
+	fyne.Key1, fyne.Key2, fyne.Key3, fyne.Key4, fyne.Key5, fyne.Key6, fyne.Key7, fyne.Key8, fyne.Key9,
+}
+
+// createFavoritesBar returns the row of one-click template buttons for the Time Tracker tab
+func (ui *MainUI) createFavoritesBar() fyne.CanvasObject {
+	ui.favoritesBox = container.NewHBox()
+	ui.rebuildFavorites()
+	manageBtn := NewTerminalButton("Templates", func() {
+		ui.showTemplatesDialog()
+	})
+	return container.NewBorder(nil, nil, nil, manageBtn, container.NewHScroll(ui.favoritesBox))
+}
+
+// rebuildFavorites redraws one button per template, numbered for its shortcut
+func (ui *MainUI) rebuildFavorites() {
+	buttons := make([]fyne.CanvasObject, 0, len(ui.config.Templates))
+	for i, t := range ui.config.Templates {
+		i, label := i, t.Label()
+		if i < len(templateShortcutKeys) {
+			label = fmt.Sprintf("%d %s", i+1, label)
+		}
+		buttons = append(buttons, NewTerminalButton(label, func() {
+			ui.startTemplate(i)
+		}))
+	}
+	if len(buttons) == 0 {
+		hint := canvas.NewText("No templates yet", color.RGBA{R: 180, G: 180, B: 180, A: 255})
+		hint.TextStyle = fyne.TextStyle{Italic: true, Monospace: true}
+		buttons = append(buttons, hint)
+	}
+	ui.favoritesBox.Objects = buttons
+	ui.favoritesBox.Refresh()
+}
+
+// registerTemplateShortcuts binds Ctrl+1 to Ctrl+9 to the first nine templates
+func (ui *MainUI) registerTemplateShortcuts() {
+	windows := fyne.CurrentApp().Driver().AllWindows()
+	if len(windows) == 0 {
+		return
+	}
+	for i, key := range templateShortcutKeys {
+		i := i
+		shortcut := &desktop.CustomShortcut{KeyName: key, Modifier: fyne.KeyModifierShortcutDefault}
+		windows[0].Canvas().AddShortcut(shortcut, func(fyne.Shortcut) {
+			ui.startTemplate(i)
+		})
+	}
+}
+
+// startTemplate starts a timer from the template at index i, together with
+// its countdown if it has one
+func (ui *MainUI) startTemplate(i int) {
+	ui.mu.Lock()
+	if i >= len(ui.config.Templates) {
+		ui.mu.Unlock()
+		return
+	}
+	t := ui.config.Templates[i]
+	sess := t.NewSession()
+	sess.Notes = strings.TrimSpace(ui.notesEntry.Text)
+	ui.estimates[sess] = t.Estimate()
+	if err := ui.engine.Start(sess); err != nil {
+		delete(ui.estimates, sess)
+		ui.mu.Unlock()
+		dialog.NewError(err, fyne.CurrentApp().Driver().AllWindows()[0]).Show()
+		return
+	}
+	ui.notesEntry.SetText("")
+	ui.mu.Unlock()
+
+	if countdown := t.Countdown(); countdown > 0 && ui.startCountdown != nil {
+		ui.startCountdown(countdown)
+	}
+}
+
+// updateActivitySuggestions reloads the autocomplete options from recent
+// sessions. The caller must hold ui.mu.
+func (ui *MainUI) updateActivitySuggestions() {
+	now := time.Now()
+	sessions, _ := ui.storage.LoadSessionsInRange(tracker.DayStart(now).AddDate(0, 0, -recentActivityDays), now)
+	ui.recentActivities = tracker.RecentActivities(sessions, maxRecentActivities)
+	ui.filterActivitySuggestions(ui.activityEntry.Text)
+}
+
+// filterActivitySuggestions narrows the autocomplete options to those containing text
+func (ui *MainUI) filterActivitySuggestions(text string) {
+	query := strings.ToLower(strings.TrimSpace(text))
+	var options []string
+	for _, a := range ui.recentActivities {
+		if strings.Contains(strings.ToLower(a), query) && a != text {
+			options = append(options, a)
+		}
+	}
+	ui.activityEntry.SetOptions(options)
+}
+
+// showTemplatesDialog lists the saved templates and lets the user add or remove them
+func (ui *MainUI) showTemplatesDialog() {
+	win := fyne.CurrentApp().Driver().AllWindows()[0]
+
+	var list *widget.List
+	list = widget.NewList(
+		func() int { return len(ui.config.Templates) },
+		func() fyne.CanvasObject {
+			label := canvas.NewText("", color.RGBA{R: 180, G: 180, B: 180, A: 255})
+			label.TextStyle = fyne.TextStyle{Monospace: true}
+			return container.NewBorder(nil, nil, nil, NewTerminalButton("Delete", nil), label)
+		},
+		func(i int, o fyne.CanvasObject) {
+			if i >= len(ui.config.Templates) {
+				return
+			}
+			t := ui.config.Templates[i]
+			row := o.(*fyne.Container)
+			label := row.Objects[0].(*canvas.Text)
+			label.Text = templateSummary(t)
+			if i < len(templateShortcutKeys) {
+				label.Text = fmt.Sprintf("Ctrl+%d %s", i+1, label.Text)
+			}
+			canvas.Refresh(label)
+			row.Objects[1].(*TerminalButton).OnTap = func() {
+				ui.mu.Lock()
+				ui.config.Templates = append(ui.config.Templates[:i], ui.config.Templates[i+1:]...)
+				ui.mu.Unlock()
+				ui.saveConfig()
+				list.Refresh()
+				ui.rebuildFavorites()
+			}
+		},
+	)
+
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder("Name (optional)")
+	activityEntry := widget.NewEntry()
+	activityEntry.SetPlaceHolder("Activity (e.g. study:math @thesis)")
+	tagEntry := widget.NewEntry()
+	tagEntry.SetPlaceHolder("Tags (optional)")
+	estimateEntry := widget.NewEntry()
+	estimateEntry.SetPlaceHolder("Estimate min")
+	countdownEntry := widget.NewEntry()
+	countdownEntry.SetPlaceHolder("Countdown min")
+	billableCheck := widget.NewCheck("Billable", nil)
+
+	fromCurrentBtn := NewTerminalButton("Use Current Entries", func() {
+		activityEntry.SetText(ui.activityEntry.Text)
+		tagEntry.SetText(ui.tagEntry.Text)
+		billableCheck.SetChecked(ui.billableCheck.Checked)
+	})
+	addBtn := NewTerminalButton("Add", func() {
+		tags, err := parseTags(tagEntry.Text)
+		if err != nil {
+			dialog.NewError(err, win).Show()
+			return
+		}
+		estimate, err := parseMinutes(estimateEntry.Text)
+		if err != nil {
+			dialog.NewError(fmt.Errorf("estimate %w", err), win).Show()
+			return
+		}
+		countdown, err := parseMinutes(countdownEntry.Text)
+		if err != nil {
+			dialog.NewError(fmt.Errorf("countdown %w", err), win).Show()
+			return
+		}
+		t := tracker.TemplateFromText(strings.TrimSpace(nameEntry.Text), strings.TrimSpace(activityEntry.Text), tags)
+		t.Billable = billableCheck.Checked
+		t.EstimateMinutes = estimate
+		t.CountdownMinutes = countdown
+		if err := t.Validate(); err != nil {
+			dialog.NewError(err, win).Show()
+			return
+		}
+		ui.mu.Lock()
+		ui.config.Templates = append(ui.config.Templates, t)
+		ui.mu.Unlock()
+		ui.saveConfig()
+		nameEntry.SetText("")
+		activityEntry.SetText("")
+		tagEntry.SetText("")
+		estimateEntry.SetText("")
+		countdownEntry.SetText("")
+		billableCheck.SetChecked(false)
+		list.Refresh()
+		ui.rebuildFavorites()
+	})
+
+	form := container.NewVBox(
+		container.NewGridWithColumns(2, nameEntry, activityEntry),
+		container.NewGridWithColumns(3, tagEntry, estimateEntry, countdownEntry),
+		billableCheck,
+		container.NewGridWithColumns(2, fromCurrentBtn, addBtn),
+	)
+	listScroll := container.NewVScroll(list)
+	listScroll.SetMinSize(fyne.NewSize(480, 180))
+
+	d := dialog.NewCustom("Templates", "Close", container.NewBorder(nil, form, nil, nil, listScroll), win)
+	d.Resize(fyne.NewSize(600, 440))
+	d.Show()
+}
+
+// templateSummary describes a template on one line for the templates list
+func templateSummary(t tracker.Template) string {
+	text := t.ActivityText()
+	if t.Name != "" {
+		text = t.Name + ": " + text
+	}
+	if len(t.Tags) > 0 {
+		text += " [" + strings.Join(t.Tags, ", ") + "]"
+	}
+	if t.EstimateMinutes > 0 {
+		text += fmt.Sprintf(" ~%dm", t.EstimateMinutes)
+	}
+	if t.CountdownMinutes > 0 {
+		text += fmt.Sprintf(" (countdown %dm)", t.CountdownMinutes)
+	}
+	return text
+}
+
+// parseMinutes reads an optional whole number of minutes, blank meaning 0
+func parseMinutes(text string) (int, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return 0, nil
+	}
+	minutes, err := strconv.Atoi(text)
+	if err != nil || minutes < 0 {
+		return 0, fmt.Errorf("must be a whole number of minutes")
+	}
+	return minutes, nil
+}
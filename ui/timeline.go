@@ -0,0 +1,331 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"katana/tracker"
+	"sort"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+)
+
+// timelineZooms are the visible spans of the day timeline, in hours
+var timelineZooms = []float64{24, 12, 6, 3, 1}
+
+// timelineAxisHeight is the space under the bars for hour labels
+const timelineAxisHeight float32 = 14
+
+// dayTimeline draws a day's sessions as bars spanning their exact minutes.
+// Concurrent sessions are stacked in lanes; untracked gaps stay dark and are
+// underlined in grey. Hovering shows the session or gap under the pointer.
+type dayTimeline struct {
+	widget.BaseWidget
+	day       time.Time
+	sessions  []*tracker.Session
+	lanes     []int // Lane of each session
+	laneCount int
+	zoom      int     // Index into timelineZooms
+	start     float64 // First visible hour
+	info      *canvas.Text
+	rangeText *canvas.Text
+}
+
+// newDayTimeline creates an empty timeline showing the whole day
+func newDayTimeline() *dayTimeline {
+	grey := color.RGBA{R: 180, G: 180, B: 180, A: 255}
+	t := &dayTimeline{laneCount: 1}
+	t.info = canvas.NewText("Hover a bar for details", grey)
+	t.info.TextStyle = fyne.TextStyle{Monospace: true}
+	t.rangeText = canvas.NewText("", grey)
+	t.rangeText.TextStyle = fyne.TextStyle{Monospace: true}
+	t.ExtendBaseWidget(t)
+	t.updateRange()
+	return t
+}
+
+// SetSessions shows the sessions of day, clipped to that day
+func (t *dayTimeline) SetSessions(day time.Time, sessions []*tracker.Session) {
+	t.day = tracker.DayStart(day)
+	t.sessions = tracker.SessionsForDay(sessions, t.day)
+	sort.Slice(t.sessions, func(i, j int) bool { return t.sessions[i].StartTime.Before(t.sessions[j].StartTime) })
+	// Greedy lane assignment: each session takes the first lane that is free by its start
+	t.lanes = make([]int, len(t.sessions))
+	var laneEnds []time.Time
+	for i, s := range t.sessions {
+		lane := -1
+		for l, end := range laneEnds {
+			if !end.After(s.StartTime) {
+				lane = l
+				break
+			}
+		}
+		if lane < 0 {
+			lane = len(laneEnds)
+			laneEnds = append(laneEnds, time.Time{})
+		}
+		laneEnds[lane] = s.EndTime
+		t.lanes[i] = lane
+	}
+	t.laneCount = len(laneEnds)
+	if t.laneCount == 0 {
+		t.laneCount = 1
+	}
+	t.Refresh()
+}
+
+// Controls returns the zoom and pan buttons with the visible range and hover label
+func (t *dayTimeline) Controls() fyne.CanvasObject {
+	zoomOut := NewTerminalButton("-", func() { t.setZoom(t.zoom - 1) })
+	zoomIn := NewTerminalButton("+", func() { t.setZoom(t.zoom + 1) })
+	earlier := NewTerminalButton("<", func() { t.pan(-timelineZooms[t.zoom] / 2) })
+	later := NewTerminalButton(">", func() { t.pan(timelineZooms[t.zoom] / 2) })
+	return container.NewVBox(
+		container.NewHBox(earlier, zoomOut, container.NewCenter(t.rangeText), zoomIn, later),
+		t.info,
+	)
+}
+
+// setZoom changes the visible span, keeping its centre where possible
+func (t *dayTimeline) setZoom(zoom int) {
+	if zoom < 0 || zoom >= len(timelineZooms) {
+		return
+	}
+	centre := t.start + timelineZooms[t.zoom]/2
+	t.zoom = zoom
+	t.start = centre - timelineZooms[zoom]/2
+	t.pan(0)
+}
+
+// pan moves the visible span by hours, staying within the day
+func (t *dayTimeline) pan(hours float64) {
+	t.start += hours
+	if max := 24 - timelineZooms[t.zoom]; t.start > max {
+		t.start = max
+	}
+	if t.start < 0 {
+		t.start = 0
+	}
+	t.updateRange()
+	t.Refresh()
+}
+
+// updateRange shows the visible span next to the controls
+func (t *dayTimeline) updateRange() {
+	from := time.Duration(t.start * float64(time.Hour))
+	to := from + time.Duration(timelineZooms[t.zoom]*float64(time.Hour))
+	t.rangeText.Text = fmt.Sprintf("%s - %s", clockOfDay(from), clockOfDay(to))
+	canvas.Refresh(t.rangeText)
+}
+
+// hoursOf returns the time of day of at in hours since the timeline's midnight
+func (t *dayTimeline) hoursOf(at time.Time) float64 {
+	return at.Sub(t.day).Hours()
+}
+
+// Scrolled pans the timeline with the mouse wheel
+func (t *dayTimeline) Scrolled(ev *fyne.ScrollEvent) {
+	step := ev.Scrolled.DX
+	if step == 0 {
+		step = -ev.Scrolled.DY
+	}
+	t.pan(float64(step) / 100 * timelineZooms[t.zoom] / 4)
+}
+
+func (t *dayTimeline) MouseIn(*desktop.MouseEvent) {}
+
+func (t *dayTimeline) MouseOut() {}
+
+// MouseMoved describes the session or gap under the pointer
+func (t *dayTimeline) MouseMoved(ev *desktop.MouseEvent) {
+	size := t.Size()
+	if size.Width <= 0 {
+		return
+	}
+	hours := t.start + float64(ev.Position.X/size.Width)*timelineZooms[t.zoom]
+	at := t.day.Add(time.Duration(hours * float64(time.Hour)))
+	laneHeight := (size.Height - timelineAxisHeight) / float32(t.laneCount)
+	lane := int(ev.Position.Y / laneHeight)
+
+	text := ""
+	for i, s := range t.sessions {
+		if t.lanes[i] == lane && !at.Before(s.StartTime) && at.Before(s.EndTime) {
+			text = fmt.Sprintf("%s-%s %s | %s", s.StartTime.Format("15:04"), s.EndTime.Format("15:04"),
+				formatMinutesShort(s.Duration), sessionSummary(s))
+			break
+		}
+	}
+	if text == "" {
+		from, to := t.gapAround(at)
+		text = fmt.Sprintf("%s-%s %s | untracked", from.Format("15:04"), to.Format("15:04"), formatMinutesShort(to.Sub(from)))
+	}
+	t.info.Text = text
+	canvas.Refresh(t.info)
+}
+
+// gapAround returns the untracked stretch containing at
+func (t *dayTimeline) gapAround(at time.Time) (time.Time, time.Time) {
+	from, to := t.day, t.day.AddDate(0, 0, 1)
+	for _, s := range t.sessions {
+		if !s.EndTime.After(at) && s.EndTime.After(from) {
+			from = s.EndTime
+		}
+		if s.StartTime.After(at) && s.StartTime.Before(to) {
+			to = s.StartTime
+		}
+	}
+	return from, to
+}
+
+// gaps returns the untracked stretches between the first and last session
+func (t *dayTimeline) gaps() [][2]time.Time {
+	var gaps [][2]time.Time
+	var covered time.Time
+	for _, s := range t.sessions {
+		if !covered.IsZero() && s.StartTime.Sub(covered) >= time.Minute {
+			gaps = append(gaps, [2]time.Time{covered, s.StartTime})
+		}
+		if s.EndTime.After(covered) {
+			covered = s.EndTime
+		}
+	}
+	return gaps
+}
+
+func (t *dayTimeline) CreateRenderer() fyne.WidgetRenderer {
+	r := &dayTimelineRenderer{timeline: t, background: canvas.NewRectangle(color.Black)}
+	r.background.StrokeColor = color.RGBA{R: 0, G: 255, B: 0, A: 255}
+	r.background.StrokeWidth = 1
+	r.rebuild()
+	return r
+}
+
+type dayTimelineRenderer struct {
+	timeline   *dayTimeline
+	background *canvas.Rectangle
+	ticks      []fyne.CanvasObject // Line and label per tick
+	tickHours  []float64
+	bars       []*canvas.Rectangle
+	gapMarks   []*canvas.Rectangle
+	objects    []fyne.CanvasObject
+}
+
+// tickStep returns the hours between axis ticks for the current zoom
+func (r *dayTimelineRenderer) tickStep() float64 {
+	switch span := timelineZooms[r.timeline.zoom]; {
+	case span > 12:
+		return 2
+	case span > 3:
+		return 1
+	case span > 1:
+		return 0.5
+	default:
+		return 0.25
+	}
+}
+
+// rebuild recreates the canvas objects for the current sessions and zoom
+func (r *dayTimelineRenderer) rebuild() {
+	t := r.timeline
+	grey := color.RGBA{R: 180, G: 180, B: 180, A: 255}
+	r.ticks, r.tickHours, r.bars, r.gapMarks = nil, nil, nil, nil
+	step := r.tickStep()
+	for h := 0.0; h <= 24; h += step {
+		line := canvas.NewLine(color.RGBA{R: 0, G: 80, B: 0, A: 255})
+		line.StrokeWidth = 1
+		label := canvas.NewText(clockOfDay(time.Duration(h*float64(time.Hour))), grey)
+		label.TextSize = 9
+		label.TextStyle = fyne.TextStyle{Monospace: true}
+		r.ticks = append(r.ticks, line, label)
+		r.tickHours = append(r.tickHours, h)
+	}
+	for _, s := range t.sessions {
+		bar := canvas.NewRectangle(colorForCategory(s.Category))
+		bar.StrokeColor = color.Black
+		bar.StrokeWidth = 1
+		r.bars = append(r.bars, bar)
+	}
+	for range t.gaps() {
+		r.gapMarks = append(r.gapMarks, canvas.NewRectangle(color.RGBA{R: 90, G: 90, B: 90, A: 255}))
+	}
+	r.objects = []fyne.CanvasObject{r.background}
+	r.objects = append(r.objects, r.ticks...)
+	for _, g := range r.gapMarks {
+		r.objects = append(r.objects, g)
+	}
+	for _, b := range r.bars {
+		r.objects = append(r.objects, b)
+	}
+}
+
+func (r *dayTimelineRenderer) Layout(size fyne.Size) {
+	t := r.timeline
+	r.background.Resize(size)
+	span := timelineZooms[t.zoom]
+	x := func(hours float64) float32 {
+		return float32((hours - t.start) / span * float64(size.Width))
+	}
+	clamp := func(v float32) float32 {
+		if v < 0 {
+			return 0
+		}
+		if v > size.Width {
+			return size.Width
+		}
+		return v
+	}
+	barArea := size.Height - timelineAxisHeight
+	for i, h := range r.tickHours {
+		line := r.ticks[2*i].(*canvas.Line)
+		label := r.ticks[2*i+1].(*canvas.Text)
+		px := x(h)
+		visible := px >= 0 && px <= size.Width
+		line.Hidden, label.Hidden = !visible, !visible || px+label.MinSize().Width > size.Width
+		line.Position1 = fyne.NewPos(px, 0)
+		line.Position2 = fyne.NewPos(px, barArea)
+		label.Move(fyne.NewPos(px+2, barArea))
+		label.Resize(label.MinSize())
+	}
+	laneHeight := barArea / float32(t.laneCount)
+	for i, s := range t.sessions {
+		left, right := clamp(x(t.hoursOf(s.StartTime))), clamp(x(t.hoursOf(s.EndTime)))
+		bar := r.bars[i]
+		bar.Hidden = right <= left
+		// Keep very short sessions visible as a sliver
+		if right-left < 1 {
+			right = left + 1
+		}
+		bar.Move(fyne.NewPos(left, float32(t.lanes[i])*laneHeight+2))
+		bar.Resize(fyne.NewSize(right-left, laneHeight-4))
+	}
+	for i, g := range t.gaps() {
+		left, right := clamp(x(t.hoursOf(g[0]))), clamp(x(t.hoursOf(g[1])))
+		mark := r.gapMarks[i]
+		mark.Hidden = right <= left
+		mark.Move(fyne.NewPos(left, barArea-3))
+		mark.Resize(fyne.NewSize(right-left, 2))
+	}
+}
+
+func (r *dayTimelineRenderer) MinSize() fyne.Size {
+	return fyne.NewSize(480, 24*float32(r.timeline.laneCount)+timelineAxisHeight)
+}
+
+func (r *dayTimelineRenderer) Refresh() {
+	r.rebuild()
+	r.Layout(r.timeline.Size())
+	canvas.Refresh(r.timeline)
+}
+
+func (r *dayTimelineRenderer) Objects() []fyne.CanvasObject { return r.objects }
+func (r *dayTimelineRenderer) Destroy()                     {}
+
+// clockOfDay formats an offset from midnight as HH:MM
+func clockOfDay(d time.Duration) string {
+	minutes := int(d.Round(time.Minute).Minutes())
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
@@ -0,0 +1,208 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"image/color"
+	"io"
+	"katana/tracker"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/gen2brain/beeep"
+)
+
+// runningTimer is the row shown for a session running in the engine, with
+// its own timer display and stop button
+type runningTimer struct {
+	session  *tracker.Session
+	display  *canvas.Text
+	estimate time.Duration // From the template the session was started with, 0 for none
+}
+
+// startTimeTrackerUpdates subscribes the Time Tracker tab to the tracking
+// engine and starts the engine's clock
+func (ui *MainUI) startTimeTrackerUpdates(timerText *canvas.Text) {
+	ui.engine.Subscribe(func(ev tracker.Event) {
+		ui.handleEngineEvent(ev, timerText)
+	})
+	interval := 500 * time.Millisecond
+	if ui.config.TimerUpdateIntervalMs > 0 {
+		interval = time.Duration(ui.config.TimerUpdateIntervalMs) * time.Millisecond
+	}
+	go ui.engine.Run(context.Background(), interval)
+}
+
+// handleEngineEvent mirrors engine state changes in the widgets. Events may
+// arrive on any goroutine, so widget work is moved to the Fyne thread.
+func (ui *MainUI) handleEngineEvent(ev tracker.Event, timerText *canvas.Text) {
+	switch ev.Kind {
+	case tracker.EventStarted:
+		fyne.Do(func() {
+			ui.mu.Lock()
+			defer ui.mu.Unlock()
+			ui.addTimerRow(ev.Session)
+		})
+	case tracker.EventStopped:
+		fyne.Do(func() {
+			ui.mu.Lock()
+			defer ui.mu.Unlock()
+			ui.removeTimerRow(ev.Session)
+			ui.refreshSessions()
+		})
+	case tracker.EventThreshold:
+		threshold := ui.config.NotificationThresholdHours
+		beeep.Notify("Katana Time Tracker", fmt.Sprintf("%s running over %g hours!", ev.Session.Activity, threshold), "")
+		// Play classic alarm sound for 2 seconds
+		go func() {
+			ui.soundPlayer.PlaySound("Classic Alarm 995", 2*time.Second)
+		}()
+	case tracker.EventTick, tracker.EventPaused, tracker.EventResumed:
+		fyne.Do(func() {
+			ui.mu.Lock()
+			defer ui.mu.Unlock()
+			ui.updateTimerDisplays(timerText)
+		})
+	case tracker.EventDayChanged, tracker.EventSplit:
+		// A new day: reload today's list and the viewers, including any
+		// part of a running session that was saved at midnight
+		fyne.Do(func() {
+			ui.mu.Lock()
+			defer ui.mu.Unlock()
+			// Follow the new day if yesterday was being viewed
+			if ev.Kind == tracker.EventDayChanged && ui.viewDate.Equal(tracker.DayStart(ev.Time).AddDate(0, 0, -1)) {
+				ui.selectViewDate(ev.Time)
+			}
+			ui.refreshSessions()
+			ui.updateActivityListPlaceholder()
+		})
+	}
+}
+
+// isTracking reports whether any timer is running
+func (ui *MainUI) isTracking() bool {
+	return ui.engine.IsTracking()
+}
+
+// addTimerRow adds a row for a session the engine started. The caller must hold ui.mu.
+func (ui *MainUI) addTimerRow(sess *tracker.Session) {
+	terminalGreen := color.RGBA{R: 0, G: 255, B: 0, A: 255}
+	display := canvas.NewText("00:00:00", terminalGreen)
+	display.TextStyle = fyne.TextStyle{Monospace: true, Bold: true}
+	ui.timers = append(ui.timers, &runningTimer{session: sess, display: display, estimate: ui.estimates[sess]})
+	delete(ui.estimates, sess)
+	ui.rebuildTimerRows()
+}
+
+// removeTimerRow drops the row of a stopped session. The caller must hold ui.mu.
+func (ui *MainUI) removeTimerRow(sess *tracker.Session) {
+	for i, t := range ui.timers {
+		if t.session == sess {
+			ui.timers = append(ui.timers[:i], ui.timers[i+1:]...)
+			break
+		}
+	}
+	ui.rebuildTimerRows()
+}
+
+// stopTimer asks the engine to stop and save one running session
+func (ui *MainUI) stopTimer(t *runningTimer) {
+	if err := ui.engine.Stop(t.session); err != nil {
+		log.SetOutput(os.Stderr)
+		log.Printf("Failed to stop session: %v", err)
+		log.SetOutput(io.Discard)
+		dialog.NewError(err, fyne.CurrentApp().Driver().AllWindows()[0]).Show()
+	}
+}
+
+// rebuildTimerRows redraws one row per running timer. The caller must hold ui.mu.
+func (ui *MainUI) rebuildTimerRows() {
+	rows := make([]fyne.CanvasObject, 0, len(ui.timers))
+	for _, t := range ui.timers {
+		t := t
+		label := canvas.NewText(timerTitle(t.session), color.RGBA{R: 180, G: 180, B: 180, A: 255})
+		label.TextStyle = fyne.TextStyle{Monospace: true}
+		notesBtn := NewTerminalButton("Notes", func() {
+			ui.showTimerNotesDialog(t)
+		})
+		stopBtn := NewTerminalButton("Stop", func() {
+			ui.stopTimer(t)
+		})
+		rows = append(rows, container.NewBorder(nil, nil, nil,
+			container.NewHBox(t.display, notesBtn, stopBtn), label))
+	}
+	ui.timersBox.Objects = rows
+	ui.timersBox.Refresh()
+}
+
+// timerTitle labels a running timer row with its activity and tags
+func timerTitle(s *tracker.Session) string {
+	title := s.Activity
+	if s.Category != "" {
+		title = s.Category + ":" + title
+	}
+	if len(s.Tags) > 0 {
+		title += " [" + strings.Join(s.Tags, ", ") + "]"
+	}
+	return title
+}
+
+// showTimerNotesDialog edits the notes of a running session
+func (ui *MainUI) showTimerNotesDialog(t *runningTimer) {
+	win := fyne.CurrentApp().Driver().AllWindows()[0]
+	notesEntry := widget.NewMultiLineEntry()
+	notesEntry.Wrapping = fyne.TextWrapWord
+	notesEntry.SetMinRowsVisible(6)
+	notesEntry.SetText(ui.engine.Notes(t.session))
+	items := []*widget.FormItem{widget.NewFormItem("Notes", notesEntry)}
+	form := dialog.NewForm("Notes: "+t.session.Activity, "Save", "Cancel", items, func(ok bool) {
+		if ok {
+			ui.engine.SetNotes(t.session, strings.TrimSpace(notesEntry.Text))
+		}
+	}, win)
+	form.Resize(fyne.NewSize(460, 320))
+	form.Show()
+}
+
+// updateTimerDisplays refreshes each timer row and the combined display.
+// The caller must hold ui.mu.
+func (ui *MainUI) updateTimerDisplays(timerText *canvas.Text) {
+	status := make(map[*tracker.Session]tracker.TimerStatus)
+	for _, st := range ui.engine.Status() {
+		status[st.Session] = st
+	}
+	for _, t := range ui.timers {
+		st, ok := status[t.session]
+		if !ok {
+			continue
+		}
+		t.display.Text = formatClock(st.Elapsed)
+		if t.estimate > 0 {
+			// Past the estimate the timer turns yellow
+			t.display.Text += " / " + formatClock(t.estimate)
+			t.display.Color = color.RGBA{R: 0, G: 255, B: 0, A: 255}
+			if st.Elapsed > t.estimate {
+				t.display.Color = color.RGBA{R: 255, G: 255, B: 0, A: 255}
+			}
+		}
+		if st.Paused {
+			t.display.Text += " (idle)"
+		}
+		canvas.Refresh(t.display)
+	}
+	// The main display shows all running timers combined under the overlap policy
+	timerText.Text = formatClock(tracker.TotalTracked(ui.engine.Snapshots(), ui.config.OverlapPolicy))
+	canvas.Refresh(timerText)
+}
+
+// formatClock renders a duration as HH:MM:SS
+func formatClock(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d:%02d", int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60)
+}
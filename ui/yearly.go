@@ -0,0 +1,267 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"katana/tracker"
+	"sort"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+)
+
+// allCategories is the category filter entry that shows every session
+const allCategories = "All categories"
+
+// heatColors are the fills for heat levels 0 to tracker.HeatLevels
+var heatColors = []color.Color{
+	color.Black,
+	color.RGBA{R: 0, G: 70, B: 0, A: 255},
+	color.RGBA{R: 0, G: 130, B: 0, A: 255},
+	color.RGBA{R: 0, G: 190, B: 0, A: 255},
+	color.RGBA{R: 0, G: 255, B: 0, A: 255},
+}
+
+// heatCell is one day of the yearly heatmap; it reports hovers and taps
+type heatCell struct {
+	widget.BaseWidget
+	rect    *canvas.Rectangle
+	onHover func()
+	onTap   func()
+}
+
+func newHeatCell(fill color.Color, onHover, onTap func()) *heatCell {
+	rect := canvas.NewRectangle(fill)
+	rect.StrokeColor = color.RGBA{R: 40, G: 80, B: 40, A: 255}
+	rect.StrokeWidth = 1
+	rect.SetMinSize(fyne.NewSize(11, 11))
+	c := &heatCell{rect: rect, onHover: onHover, onTap: onTap}
+	c.ExtendBaseWidget(c)
+	return c
+}
+
+func (c *heatCell) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(c.rect)
+}
+
+func (c *heatCell) Tapped(*fyne.PointEvent) {
+	if c.onHover != nil {
+		c.onHover()
+	}
+	if c.onTap != nil {
+		c.onTap()
+	}
+}
+
+func (c *heatCell) MouseIn(*desktop.MouseEvent) {
+	c.rect.StrokeColor = color.White
+	c.rect.Refresh()
+	if c.onHover != nil {
+		c.onHover()
+	}
+}
+
+func (c *heatCell) MouseMoved(*desktop.MouseEvent) {}
+
+func (c *heatCell) MouseOut() {
+	c.rect.StrokeColor = color.RGBA{R: 40, G: 80, B: 40, A: 255}
+	c.rect.Refresh()
+}
+
+// yearViewer is the Yearly viewer: a heatmap of one year with a category
+// filter and year navigation. It keeps its state across refreshes.
+type yearViewer struct {
+	ui       *MainUI
+	year     int
+	category string
+	content  *fyne.Container
+}
+
+// newYearViewer creates the Yearly viewer showing the current year
+func (ui *MainUI) newYearViewer() *yearViewer {
+	v := &yearViewer{ui: ui, year: time.Now().Year(), category: allCategories, content: container.NewMax()}
+	v.refresh()
+	return v
+}
+
+// refresh reloads the year's sessions and redraws the heatmap
+func (v *yearViewer) refresh() {
+	terminalGreen := color.RGBA{R: 0, G: 255, B: 0, A: 255}
+	grey := color.RGBA{R: 180, G: 180, B: 180, A: 255}
+
+	first := time.Date(v.year, time.January, 1, 0, 0, 0, 0, time.Local)
+	next := first.AddDate(1, 0, 0)
+	sessions, _ := v.ui.storage.LoadSessionsInRange(first, next)
+	sessions = tracker.ClipSessions(sessions, first, next)
+
+	categories := map[string]bool{}
+	var filtered []*tracker.Session
+	for _, s := range sessions {
+		if s.Category != "" {
+			categories[s.Category] = true
+		}
+		if v.category == allCategories || strings.EqualFold(s.Category, v.category) {
+			filtered = append(filtered, s)
+		}
+	}
+	totals := tracker.DailyTotals(filtered, v.ui.config.OverlapPolicy)
+	var max, yearTotal time.Duration
+	for _, t := range totals {
+		yearTotal += t.Total
+		if t.Total > max {
+			max = t.Total
+		}
+	}
+
+	info := canvas.NewText("Hover or tap a day", grey)
+	info.TextStyle = fyne.TextStyle{Monospace: true}
+
+	// Columns are weeks starting on Sunday, rows are weekdays
+	start := first.AddDate(0, 0, -int(first.Weekday()))
+	weeks := (int(next.Sub(start).Hours()/24) + 6) / 7
+	cells := []fyne.CanvasObject{layoutSpacer()}
+	for w := 0; w < weeks; w++ {
+		label := ""
+		if sunday := start.AddDate(0, 0, 7*w); sunday.AddDate(0, 0, 6).Day() <= 7 && sunday.AddDate(0, 0, 6).Year() == v.year {
+			label = sunday.AddDate(0, 0, 6).Format("Jan")
+		}
+		month := canvas.NewText(label, grey)
+		month.TextSize = 9
+		cells = append(cells, month)
+	}
+	for r := 0; r < 7; r++ {
+		name := ""
+		if r%2 == 1 {
+			name = time.Weekday(r).String()[:3]
+		}
+		dayLabel := canvas.NewText(name, grey)
+		dayLabel.TextStyle = fyne.TextStyle{Monospace: true}
+		dayLabel.TextSize = 9
+		cells = append(cells, dayLabel)
+		for w := 0; w < weeks; w++ {
+			date := start.AddDate(0, 0, 7*w+r)
+			if date.Year() != v.year {
+				cells = append(cells, layoutSpacer())
+				continue
+			}
+			t := totals[date]
+			t.Date = date
+			cells = append(cells, newHeatCell(heatColors[tracker.HeatLevel(t.Total, max)],
+				func() {
+					info.Text = dayTotalSummary(t)
+					canvas.Refresh(info)
+				},
+				func() {
+					v.ui.showDaySessions(date, v.category)
+				}))
+		}
+	}
+
+	prevBtn := NewTerminalButton("<", func() {
+		v.year--
+		v.refresh()
+	})
+	nextBtn := NewTerminalButton(">", func() {
+		v.year++
+		v.refresh()
+	})
+	yearLabel := canvas.NewText(fmt.Sprintf("%d  %.1fh", v.year, yearTotal.Hours()), terminalGreen)
+	yearLabel.TextStyle = fyne.TextStyle{Bold: true, Monospace: true}
+
+	options := []string{allCategories}
+	for c := range categories {
+		options = append(options, c)
+	}
+	sort.Strings(options[1:])
+	categorySelect := widget.NewSelect(options, nil)
+	categorySelect.SetSelected(v.category)
+	categorySelect.OnChanged = func(c string) {
+		if c != v.category {
+			v.category = c
+			v.refresh()
+		}
+	}
+
+	legend := []fyne.CanvasObject{canvas.NewText("less", grey)}
+	for _, c := range heatColors {
+		swatch := canvas.NewRectangle(c)
+		swatch.StrokeColor = color.RGBA{R: 40, G: 80, B: 40, A: 255}
+		swatch.StrokeWidth = 1
+		swatch.SetMinSize(fyne.NewSize(11, 11))
+		legend = append(legend, container.NewCenter(swatch))
+	}
+	legend = append(legend, canvas.NewText("more", grey))
+
+	v.content.Objects = []fyne.CanvasObject{container.NewVBox(
+		container.NewHBox(prevBtn, container.NewCenter(yearLabel), nextBtn, categorySelect),
+		container.NewGridWithColumns(weeks+1, cells...),
+		container.NewHBox(append(legend, layoutSpacer(), info)...),
+	)}
+	v.content.Refresh()
+}
+
+// dayTotalSummary describes a day's total and its categories on one line
+func dayTotalSummary(t tracker.DayTotal) string {
+	text := fmt.Sprintf("%s: %s", t.Date.Format("Mon 2006-01-02"), formatMinutesShort(t.Total))
+	var parts []string
+	for _, c := range t.Categories() {
+		name := c
+		if name == "" {
+			name = "uncategorized"
+		}
+		parts = append(parts, fmt.Sprintf("%s %s", name, formatMinutesShort(t.ByCategory[c])))
+	}
+	if len(parts) > 0 {
+		text += " (" + strings.Join(parts, ", ") + ")"
+	}
+	return text
+}
+
+// showDaySessions lists the sessions of a day, optionally limited to a category
+func (ui *MainUI) showDaySessions(day time.Time, category string) {
+	win := fyne.CurrentApp().Driver().AllWindows()[0]
+	sessions, err := ui.storage.LoadSessionsForDay(day)
+	if err != nil {
+		dialog.NewError(err, win).Show()
+		return
+	}
+	var shown []*tracker.Session
+	for _, s := range sessions {
+		if category == "" || category == allCategories || strings.EqualFold(s.Category, category) {
+			shown = append(shown, s)
+		}
+	}
+
+	var content fyne.CanvasObject
+	if len(shown) == 0 {
+		empty := canvas.NewText("Nothing tracked on this day", color.RGBA{R: 180, G: 180, B: 180, A: 255})
+		empty.TextStyle = fyne.TextStyle{Italic: true, Monospace: true}
+		content = empty
+	} else {
+		list := widget.NewList(
+			func() int { return len(shown) },
+			func() fyne.CanvasObject {
+				label := canvas.NewText("", color.RGBA{R: 180, G: 180, B: 180, A: 255})
+				label.TextStyle = fyne.TextStyle{Monospace: true}
+				return label
+			},
+			func(i int, o fyne.CanvasObject) {
+				s := shown[i]
+				label := o.(*canvas.Text)
+				label.Text = fmt.Sprintf("%s - %s %6s | %s", s.StartTime.Format("15:04"), s.EndTime.Format("15:04"),
+					formatMinutesShort(s.Duration), sessionSummary(s))
+				canvas.Refresh(label)
+			},
+		)
+		scroll := container.NewVScroll(list)
+		scroll.SetMinSize(fyne.NewSize(520, 260))
+		content = scroll
+	}
+	dialog.NewCustom(day.Format("Monday, 2 January 2006"), "Close", content, win).Show()
+}